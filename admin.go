@@ -0,0 +1,234 @@
+package anchor
+
+import (
+	"context"
+	"encoding/json"
+	"html/template"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// adminHeader must be set on POST requests to AdminHandler, unless the body is JSON.
+const adminHeader = "X-Anchor-Admin"
+
+// AdminOption for the handler from AdminHandler.
+type AdminOption func(cfg *adminConfig)
+
+type adminConfig struct {
+	shutdown bool
+	reload   func(ctx context.Context) error
+}
+
+// WithAdminShutdown enables the POST action that shuts down the Anchor.
+//
+// Default: disabled
+func WithAdminShutdown() AdminOption {
+	return func(cfg *adminConfig) {
+		cfg.shutdown = true
+	}
+}
+
+// WithAdminReload enables the POST action that calls fn to reload the application.
+//
+// Default: disabled
+func WithAdminReload(fn func(ctx context.Context) error) AdminOption {
+	return func(cfg *adminConfig) {
+		cfg.reload = fn
+	}
+}
+
+// AdminHandler returns an http.Handler with a debug page of the Anchor. It shows the
+// Status of the Components, the order they are Setup and Closed in, the configuration
+// and the recent lifecycle events.
+//
+// The page is HTML, or JSON when requested with ?format=json or an Accept header
+// of application/json. When enabled, a POST to a path ending in /shutdown or /reload
+// triggers the action. The POST must have an X-Anchor-Admin header or a JSON
+// Content-Type, so a page on another site cannot trigger it from the browser of an operator.
+//
+// Mount it with a trailing slash, e.g.
+//
+//	mux.Handle("/debug/anchor/", a.AdminHandler())
+func (a *Anchor) AdminHandler(opts ...AdminOption) http.Handler {
+	cfg := &adminConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &adminHandler{anchor: a, cfg: cfg}
+}
+
+type adminHandler struct {
+	anchor *Anchor
+	cfg    *adminConfig
+}
+
+type adminView struct {
+	Components []ComponentStatus `json:"components"`
+	SetupOrder []string          `json:"setup_order"`
+	CloseOrder []string          `json:"close_order"`
+	Config     adminConfigView   `json:"config"`
+	Events     []Event           `json:"events"`
+	Shutdown   bool              `json:"shutdown_enabled"`
+	Reload     bool              `json:"reload_enabled"`
+}
+
+type adminConfigView struct {
+	SetupTimeout      string `json:"setup_timeout"`
+	StartTimeout      string `json:"start_timeout"`
+	CloseTimeout      string `json:"close_timeout"`
+	ReadyCheckBackoff string `json:"ready_check_backoff"`
+	LeakCheck         string `json:"leak_check"`
+}
+
+func (h *adminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.action(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	view := h.view()
+	if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(view)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = adminPage.Execute(w, view)
+}
+
+func (h *adminHandler) action(w http.ResponseWriter, r *http.Request) {
+	if !adminRequest(r) {
+		http.Error(w, "missing "+adminHeader+" header", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/shutdown") && h.cfg.shutdown:
+		h.anchor.info(r.Context(), "[anchor] Shutdown requested by admin")
+		h.anchor.Shutdown()
+	case strings.HasSuffix(r.URL.Path, "/reload") && h.cfg.reload != nil:
+		h.anchor.info(r.Context(), "[anchor] Reload requested by admin")
+		if err := h.cfg.reload(r.Context()); err != nil {
+			h.anchor.error(r.Context(), "[anchor] Reload failed", errorAttr(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	default:
+		http.Error(w, "action not enabled", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// adminRequest tells if the request could not be sent by a plain form on another site.
+// A browser only sends a custom header or a JSON body to another origin after a
+// CORS preflight, which the handler does not allow.
+func adminRequest(r *http.Request) bool {
+	if r.Header.Get(adminHeader) != "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+func (h *adminHandler) view() adminView {
+	var (
+		a          = h.anchor
+		setupOrder = a.names()
+		closeOrder = slices.Clone(setupOrder)
+	)
+	slices.Reverse(closeOrder)
+
+	return adminView{
+		Components: a.Status(),
+		SetupOrder: setupOrder,
+		CloseOrder: closeOrder,
+		Config: adminConfigView{
+			SetupTimeout:      formatTimeout(a.cfg.setupTimeout),
+			StartTimeout:      formatTimeout(a.cfg.startTimeout),
+			CloseTimeout:      formatTimeout(a.cfg.closeTimeout),
+			ReadyCheckBackoff: a.cfg.readyCheckBackoffDescription,
+			LeakCheck:         formatTimeout(a.cfg.leakGrace),
+		},
+		Events:   a.events.recent(),
+		Shutdown: h.cfg.shutdown,
+		Reload:   h.cfg.reload != nil,
+	}
+}
+
+func formatTimeout(d time.Duration) string {
+	if d <= 0 {
+		return "none"
+	}
+
+	return d.String()
+}
+
+var adminPage = template.Must(template.New("admin").Funcs(template.FuncMap{
+	"time": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format("15:04:05.000")
+	},
+	"error": errorString,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><title>anchor</title></head>
+<body>
+<h1>anchor</h1>
+<h2>Components</h2>
+<table border="1">
+<tr><th>Name</th><th>Phase</th><th>Since</th><th>Setup</th><th>Start</th><th>Ready</th><th>Close</th><th>Probe error</th><th>Start error</th></tr>
+{{- range .Components}}
+<tr><td>{{.Name}}</td><td>{{.Phase}}</td><td>{{time .Changed}}</td><td>{{time .SetupEnd}}</td><td>{{time .StartBegin}}</td><td>{{time .Ready}}</td><td>{{time .CloseEnd}}</td><td>{{error .ProbeError}}</td><td>{{error .StartError}}</td></tr>
+{{- end}}
+</table>
+<h2>Order</h2>
+<p>Setup: {{range $i, $name := .SetupOrder}}{{if $i}} &rarr; {{end}}{{$name}}{{end}}</p>
+<p>Close: {{range $i, $name := .CloseOrder}}{{if $i}} &rarr; {{end}}{{$name}}{{end}}</p>
+<h2>Configuration</h2>
+<table border="1">
+<tr><td>Setup timeout</td><td>{{.Config.SetupTimeout}}</td></tr>
+<tr><td>Start timeout</td><td>{{.Config.StartTimeout}}</td></tr>
+<tr><td>Close timeout</td><td>{{.Config.CloseTimeout}}</td></tr>
+<tr><td>Ready check backoff</td><td>{{.Config.ReadyCheckBackoff}}</td></tr>
+<tr><td>Leak check</td><td>{{.Config.LeakCheck}}</td></tr>
+</table>
+<h2>Events</h2>
+<table border="1">
+<tr><th>At</th><th>Event</th><th>Component</th><th>Cause</th><th>Error</th></tr>
+{{- range .Events}}
+<tr><td>{{time .At}}</td><td>{{.Kind}}</td><td>{{.Component}}</td><td>{{.Cause}}</td><td>{{error .Err}}</td></tr>
+{{- end}}
+</table>
+{{- if .Shutdown}}
+<p><button data-action="shutdown">Shutdown</button></p>
+{{- end}}
+{{- if .Reload}}
+<p><button data-action="reload">Reload</button></p>
+{{- end}}
+{{- if or .Shutdown .Reload}}
+<script>
+for (const button of document.querySelectorAll("button[data-action]")) {
+	button.onclick = () => fetch(button.dataset.action, {method: "POST", headers: {"X-Anchor-Admin": "true"}})
+		.then(() => location.reload());
+}
+</script>
+{{- end}}
+</body>
+</html>
+`))
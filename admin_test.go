@@ -0,0 +1,233 @@
+package anchor_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestAdminHandler(t *testing.T) {
+	var (
+		runAnchor = func(t *testing.T, opts ...anchor.Option) *anchor.Anchor {
+			t.Helper()
+			wire := anchor.ManualWire()
+			a := anchor.New(wire, append(opts, anchor.WithReadyCallback(func(ctx context.Context) error {
+				wire.Stop(nil)
+				return nil
+			}))...).Add(&tracedComponent{name: "db"}, &tracedComponent{name: "http"})
+			assert.Equal(t, anchor.OK, a.Run())
+			return a
+		}
+		serve = func(handler http.Handler, method, target string, header ...string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(method, target, nil)
+			for i := 0; i < len(header); i += 2 {
+				req.Header.Set(header[i], header[i+1])
+			}
+			handler.ServeHTTP(rec, req)
+			return rec
+		}
+	)
+
+	t.Run("show json", func(t *testing.T) {
+		// arrange
+		var (
+			a   = runAnchor(t, anchor.WithCloseTimeout(time.Second), anchor.WithFixedReadyCheckBackoff(time.Millisecond))
+			sut = a.AdminHandler()
+		)
+
+		// act
+		rec := serve(sut, http.MethodGet, "/debug/anchor/?format=json")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		var got struct {
+			Components []struct {
+				Name  string `json:"name"`
+				Phase string `json:"phase"`
+			} `json:"components"`
+			SetupOrder []string `json:"setup_order"`
+			CloseOrder []string `json:"close_order"`
+			Config     struct {
+				SetupTimeout      string `json:"setup_timeout"`
+				CloseTimeout      string `json:"close_timeout"`
+				ReadyCheckBackoff string `json:"ready_check_backoff"`
+			} `json:"config"`
+			Events []struct {
+				Component string `json:"component"`
+				Kind      string `json:"kind"`
+			} `json:"events"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		if assert.Equal(t, 2, len(got.Components)) {
+			assert.Equal(t, "db", got.Components[0].Name)
+			assert.Equal(t, "closed", got.Components[0].Phase)
+		}
+		assert.EqualSlice(t, []string{"db", "http"}, got.SetupOrder)
+		assert.EqualSlice(t, []string{"http", "db"}, got.CloseOrder)
+		assert.Equal(t, "none", got.Config.SetupTimeout)
+		assert.Equal(t, "1s", got.Config.CloseTimeout)
+		assert.Equal(t, "fixed 1ms", got.Config.ReadyCheckBackoff)
+		if assert.Truef(t, len(got.Events) > 2, "events: %v", got.Events) {
+			assert.Equal(t, "run", got.Events[0].Kind)
+			assert.Equal(t, "done", got.Events[len(got.Events)-1].Kind)
+		}
+	})
+
+	t.Run("show json when accepted", func(t *testing.T) {
+		// arrange
+		var (
+			sut = runAnchor(t).AdminHandler()
+		)
+
+		// act
+		rec := serve(sut, http.MethodGet, "/debug/anchor/", "Accept", "application/json")
+
+		// assert
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("show html", func(t *testing.T) {
+		// arrange
+		var (
+			sut = runAnchor(t).AdminHandler()
+		)
+
+		// act
+		rec := serve(sut, http.MethodGet, "/debug/anchor/")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		body := rec.Body.String()
+		assert.Truef(t, strings.Contains(body, "<td>db</td><td>closed</td>"), "body: %s", body)
+		assert.Truef(t, strings.Contains(body, "Setup: db &rarr; http"), "body: %s", body)
+		assert.Falsef(t, strings.Contains(body, "<nil>"), "body: %s", body)
+		assert.Truef(t, strings.Contains(body, "<td>linear 100ms</td>"), "body: %s", body)
+		assert.Falsef(t, strings.Contains(body, `data-action="shutdown"`), "body: %s", body)
+	})
+
+	t.Run("reject disabled actions", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.ManualWire()).AdminHandler()
+		)
+
+		// act
+		rec := serve(sut, http.MethodPost, "/debug/anchor/shutdown", "X-Anchor-Admin", "true")
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("reject actions without the admin header", func(t *testing.T) {
+		// arrange
+		var (
+			wire = anchor.ManualWire()
+			a    = anchor.New(wire).Add(&tracedComponent{name: "db"})
+			sut  = a.AdminHandler(anchor.WithAdminShutdown())
+			done = make(chan int, 1)
+		)
+
+		go func() { done <- a.Run() }()
+		<-a.Ready()
+
+		// act
+		form := serve(sut, http.MethodPost, "/debug/anchor/shutdown", "Content-Type", "application/x-www-form-urlencoded")
+		plain := serve(sut, http.MethodPost, "/debug/anchor/shutdown", "Content-Type", "text/plain")
+
+		// assert
+		assert.Equal(t, http.StatusForbidden, form.Code)
+		assert.Equal(t, http.StatusForbidden, plain.Code)
+		select {
+		case <-done:
+			t.Fatal("shut down by a rejected action")
+		case <-time.After(20 * time.Millisecond):
+		}
+		wire.Stop(nil)
+		assert.Equal(t, anchor.OK, <-done)
+	})
+
+	t.Run("accept actions with a json body", func(t *testing.T) {
+		// arrange
+		var (
+			reloads int
+			sut     = anchor.New(anchor.ManualWire()).AdminHandler(anchor.WithAdminReload(func(ctx context.Context) error {
+				reloads++
+				return nil
+			}))
+		)
+
+		// act
+		rec := serve(sut, http.MethodPost, "/debug/anchor/reload", "Content-Type", "application/json; charset=utf-8")
+
+		// assert
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, 1, reloads)
+	})
+
+	t.Run("shutdown when enabled", func(t *testing.T) {
+		// arrange
+		var (
+			a   = anchor.New(anchor.ManualWire()).Add(&tracedComponent{name: "db"})
+			sut = a.AdminHandler(anchor.WithAdminShutdown())
+		)
+
+		go func() {
+			<-a.Ready()
+			serve(sut, http.MethodPost, "/debug/anchor/shutdown", "X-Anchor-Admin", "true")
+		}()
+
+		// act
+		code := a.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Truef(t, strings.Contains(serve(sut, http.MethodGet, "/").Body.String(), `data-action="shutdown"`), "shutdown button")
+	})
+
+	t.Run("reload when enabled", func(t *testing.T) {
+		// arrange
+		var (
+			reloads int
+			sut     = anchor.New(anchor.ManualWire()).AdminHandler(anchor.WithAdminReload(func(ctx context.Context) error {
+				reloads++
+				if reloads > 1 {
+					return errors.New("reload failed")
+				}
+				return nil
+			}))
+		)
+
+		// act
+		first := serve(sut, http.MethodPost, "/debug/anchor/reload", "X-Anchor-Admin", "true")
+		second := serve(sut, http.MethodPost, "/debug/anchor/reload", "X-Anchor-Admin", "true")
+
+		// assert
+		assert.Equal(t, http.StatusAccepted, first.Code)
+		assert.Equal(t, http.StatusInternalServerError, second.Code)
+		assert.Equal(t, 2, reloads)
+	})
+
+	t.Run("reject other methods", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.ManualWire()).AdminHandler()
+		)
+
+		// act
+		rec := serve(sut, http.MethodDelete, "/debug/anchor/")
+
+		// assert
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -4,20 +4,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/kyuff/anchor/internal/clock"
 	"github.com/kyuff/anchor/internal/decorate"
+	"github.com/kyuff/anchor/internal/goroutines"
 	"golang.org/x/sync/errgroup"
 )
 
 // New creates an Anchor that is bound to the Wire.
 // When the Wire is closed, the Anchor will Close all Components.
 func New(wire Wire, opts ...Option) *Anchor {
+	shutdownCtx, shutdown := context.WithCancel(context.Background())
+	cfg := applyOptions(defaultOptions(), opts...)
+	tr := newTracker()
+	events := newBroker()
+	cfg.observers = append(cfg.observers, tr, events)
 	return &Anchor{
-		cfg:       applyOptions(defaultOptions(), opts...),
-		wire:      wire,
-		closeChan: make(chan int, 1),
+		id:          strconv.FormatInt(anchorIDs.Add(1), 10),
+		cfg:         cfg,
+		tracker:     tr,
+		events:      events,
+		wire:        wire,
+		closeChan:   make(chan int, 1),
+		ready:       make(chan struct{}),
+		shutdownCtx: shutdownCtx,
+		shutdown:    shutdown,
 	}
 }
 
@@ -29,8 +45,10 @@ func New(wire Wire, opts ...Option) *Anchor {
 type Anchor struct {
 	// Wire controls the time that the application runs.
 	// The application shuts down when the context returned is cancelled.
-	wire       Wire
-	cfg        *config
+	wire Wire
+	cfg  *config
+	// id of the Anchor in the pprof labels of Component goroutines
+	id         string
 	components []fullComponent
 	running    atomic.Bool
 	// index to the last component that was setup
@@ -38,6 +56,21 @@ type Anchor struct {
 	setupIndex int
 
 	closeChan chan int
+	// starts are the calls to Start that have not returned
+	starts sync.WaitGroup
+	// ready is closed when all Components are ready
+	ready chan struct{}
+	// shutdownCtx is canceled when Shutdown is called
+	shutdownCtx context.Context
+	shutdown    context.CancelFunc
+
+	tracker *tracker
+	events  *broker
+
+	mu   sync.Mutex
+	errs []error
+	// snapshot of the goroutines when Run started
+	snapshot []goroutines.Group
 }
 
 // Add will manage the Component list by the Anchor.
@@ -56,40 +89,106 @@ func (a *Anchor) Add(components ...Component) *Anchor {
 			panic("cannot add nil component")
 		}
 
-		a.components = append(a.components, decorate.New(component))
+		decorated := decorate.New(component)
+		a.components = append(a.components, decorated)
+		a.tracker.add(decorated.Name())
 	}
 
 	return a
 }
 
+// Ready returns a channel that is closed when all Components have been Setup, Started
+// and succeeded any Probe, and the ready callback returned without error.
+func (a *Anchor) Ready() <-chan struct{} {
+	return a.ready
+}
+
+// Shutdown closes the Anchor gracefully, as if the Wire was closed.
+//
+// It is safe to call from any goroutine, more than once and before Run.
+// When called before Run, Run returns OK without calling any Component.
+func (a *Anchor) Shutdown() {
+	a.shutdown()
+}
+
+// Err returns the errors returned by Components during Run, including those from Close.
+func (a *Anchor) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return errors.Join(a.errs...)
+}
+
+func (a *Anchor) recordError(component fullComponent, phase string, err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.errs = append(a.errs, fmt.Errorf("%s %q: %w", phase, component.Name(), err))
+}
+
+// isCanceled is true when err is caused by the Anchor closing down.
+func isCanceled(ctx context.Context, err error) bool {
+	return ctx.Err() != nil && errors.Is(err, context.Canceled)
+}
+
 // Run is blocking until the Wire closes or a Component returns an error.
 // When either happens, each Component is closed in in reverse order of which they were added and started.
+// Run then waits up to the close timeout for Start of each Component to return.
 func (a *Anchor) Run() int {
 	if !a.running.CompareAndSwap(false, true) {
 		panic("anchor is already running")
 	}
 
 	if len(a.components) == 0 {
-		a.cfg.logger.ErrorfCtx(a.cfg.anchorCtx, "No components added. Aborting ...")
+		a.error(a.cfg.anchorCtx, "No components added. Aborting ...")
+		a.observe(func(o observer) { o.done(OK, a.cfg.clock.Now()) })
+		a.notifyDone(OK)
 		return OK
 	}
 
+	runCtx, span := a.cfg.tracer.Start(clock.NewContext(a.cfg.anchorCtx, a.cfg.clock), SpanRun)
+
 	// wire the anchor context
-	ctx, cancel := a.wire.Wire(a.cfg.anchorCtx)
+	ctx, cancel := a.wire.Wire(runCtx)
 	defer cancel()
 
+	// after Wire, as it may start goroutines that live as long as the process, e.g. for signals
+	a.takeSnapshot()
+
+	a.observe(func(o observer) { o.run(a.names(), a.cfg.clock.Now()) })
+
+	ctx, cancelShutdown := context.WithCancel(ctx)
+	defer cancelShutdown()
+	stopShutdown := context.AfterFunc(a.shutdownCtx, cancelShutdown)
+	defer stopShutdown()
+	if a.shutdownCtx.Err() != nil {
+		// AfterFunc runs in its own goroutine, so cancel right away when Shutdown was called before Run
+		cancelShutdown()
+	}
+
 	closed := make(chan int)
+	setupDone := make(chan struct{})
 	// monitor closeChan
 	go func() {
 		var code int
+		var cause string
 		select {
 		case <-ctx.Done():
+			a.info(a.cfg.anchorCtx, "[anchor] Shutdown", slog.Any("cause", context.Cause(ctx)))
 			code = OK
+			cause = causeContext
+			if a.shutdownCtx.Err() != nil {
+				cause = causeShutdown
+			}
 		case c := <-a.closeChan:
 			code = c
+			cause = shutdownCause(c)
 		}
+		a.observe(func(o observer) { o.shutdown(cause, a.cfg.clock.Now()) })
 
-		closeCode := a.closeAll(context.Background())
+		// setup returns when ctx is done, so wait for it to not close components while they are setup
+		<-setupDone
+		closeCode := a.closeAll(context.WithoutCancel(runCtx))
 		if code != OK {
 			closed <- code
 		} else {
@@ -97,31 +196,104 @@ func (a *Anchor) Run() int {
 		}
 	}()
 
-	if code := a.setupAll(ctx); code != OK {
-		a.signalClose(code)
+	code := OK
+	if a.shutdownCtx.Err() == nil {
+		code = a.setupAll(ctx)
 	} else {
+		// Shutdown was called before Run, so no Component is Setup or Closed
+		a.setupIndex = -1
+	}
+	close(setupDone)
+	if code != OK {
+		a.signalClose(code)
+	} else if ctx.Err() == nil {
+		// added before startAll runs, so waitStarts can not miss a Start
+		a.starts.Add(len(a.components))
 		go a.startAll(ctx)
 	}
 
-	return <-closed
+	code = <-closed
+	// Components may wait for the context to be done before they exit
+	cancelShutdown()
+	a.waitStarts()
+	a.checkLeaks()
+	a.observe(func(o observer) { o.done(code, a.cfg.clock.Now()) })
+	a.reportTimeline("shutdown")
+	a.endRun(span, code)
+	a.notifyDone(code)
+	return code
+}
+
+func (a *Anchor) endRun(span Span, code int) {
+	err := a.Err()
+	if err == nil && code != OK {
+		err = fmt.Errorf("exit code %d", code)
+	}
+
+	span.End(err, Attribute{Key: AttributeExitCode, Value: code})
+}
+
+func (a *Anchor) names() []string {
+	names := make([]string, 0, len(a.components))
+	for _, component := range a.components {
+		names = append(names, component.Name())
+	}
+
+	return names
+}
+
+func (a *Anchor) notifyReady() {
+	a.observe(func(o observer) { o.ready(a.cfg.clock.Now()) })
+	a.reportTimeline("ready")
+	close(a.ready)
+	if w, ok := a.wire.(lifecycleWire); ok {
+		w.ready()
+	}
+}
+
+func (a *Anchor) reportTimeline(at string) {
+	if a.cfg.timelineWidth <= 0 {
+		return
+	}
+
+	t := a.Timeline()
+	a.info(a.cfg.anchorCtx, "[anchor] Timeline at "+at,
+		slog.String("table", t.String()),
+		slog.String("gantt", t.Gantt(a.cfg.timelineWidth)),
+	)
+}
+
+func (a *Anchor) notifyDone(code int) {
+	if w, ok := a.wire.(lifecycleWire); ok {
+		w.done(code)
+	}
 }
 
 func (a *Anchor) signalClose(code int) {
-	a.closeChan <- code
+	select {
+	case a.closeChan <- code:
+	default:
+		// the Anchor is already closing
+	}
 }
 
 func (a *Anchor) startAll(ctx context.Context) {
 	g, startCtx := errgroup.WithContext(ctx)
 
-	for _, component := range a.components {
+	for index, component := range a.components {
 		g.Go(func() (err error) {
-			return a.startComponent(startCtx, component)
+			defer a.starts.Done()
+			a.labeled(startCtx, component, "Start", func(ctx context.Context) {
+				err = a.startComponent(ctx, index, component)
+			})
+			return err
 		})
 	}
 
-	err := a.probeAll(ctx)
+	// probe with the start context, so a Component failing to Start stops the probes
+	err := a.probeAll(startCtx)
 	if err != nil {
-		a.cfg.logger.ErrorfCtx(ctx, "[anchor] Ready check failed: %v", err)
+		a.error(ctx, "[anchor] Ready check failed", errorAttr(err))
 		a.signalClose(Internal)
 		return
 	}
@@ -132,6 +304,8 @@ func (a *Anchor) startAll(ctx context.Context) {
 		return
 	}
 
+	a.notifyReady()
+
 	err = g.Wait()
 	if err != nil {
 		a.signalClose(Internal)
@@ -140,49 +314,78 @@ func (a *Anchor) startAll(ctx context.Context) {
 	}
 }
 
-func (a *Anchor) startComponent(ctx context.Context, component fullComponent) (err error) {
+// waitStarts waits up to the close timeout for Start of the Components to return,
+// so their spans and events are done before the Run ends.
+func (a *Anchor) waitStarts() {
+	done := make(chan struct{})
+	go func() {
+		a.starts.Wait()
+		close(done)
+	}()
+
+	ctx, cancel := clock.WithTimeout(context.Background(), a.cfg.clock, a.cfg.closeTimeout)
+	defer cancel()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		a.warn(a.cfg.anchorCtx, "[anchor] Start did not return after close", durationAttr(a.cfg.closeTimeout))
+	}
+}
+
+func (a *Anchor) startComponent(ctx context.Context, index int, component fullComponent) (err error) {
+	began := a.cfg.clock.Now()
 	defer func() {
 		if panicErr := recover(); panicErr != nil {
-			a.cfg.logger.ErrorfCtx(ctx, "[anchor] Start panic for %q: %v", component.Name(), panicErr)
+			a.error(ctx, "[anchor] Start panic", componentAttr(component), phaseAttr("Start"), durationAttr(a.since(began)), errorAttr(panicErr))
 			err = errors.Join(err, fmt.Errorf("%s", panicErr))
 		}
+		if err != nil && !isCanceled(ctx, err) {
+			a.recordError(component, "Start", err)
+		}
 	}()
 
-	a.cfg.logger.InfofCtx(ctx, "[anchor] Start %q", component.Name())
-	err = component.Start(ctx)
+	a.info(ctx, "[anchor] Start", componentAttr(component), phaseAttr("Start"))
+	err = a.traced(ctx, "Start", index, component, component.Start)
 	if err != nil {
-		a.cfg.logger.ErrorfCtx(ctx, "[anchor] Start failed for %q: %v", component.Name(), err)
+		a.error(ctx, "[anchor] Start failed", componentAttr(component), phaseAttr("Start"), durationAttr(a.since(began)), errorAttr(err))
 		return err
 	}
 
-	a.cfg.logger.InfofCtx(ctx, "[anchor] Component exit: %q", component.Name())
+	a.info(ctx, "[anchor] Component exit", componentAttr(component), phaseAttr("Start"), durationAttr(a.since(began)))
 	return nil
 }
 
 func (a *Anchor) probeAll(ctx context.Context) error {
 	var cancel context.CancelFunc = func() {}
 	if a.cfg.startTimeout > 0 {
-		ctx, cancel = context.WithTimeout(ctx, a.cfg.startTimeout)
+		ctx, cancel = clock.WithTimeout(ctx, a.cfg.clock, a.cfg.startTimeout)
 	}
 
 	g, probeCtx := errgroup.WithContext(ctx)
 	defer cancel()
 
-	for _, component := range a.components {
+	for index, component := range a.components {
 		g.Go(func() (err error) {
-			return a.probeComponent(probeCtx, component)
+			a.labeled(probeCtx, component, "Probe", func(ctx context.Context) {
+				err = a.probeComponent(ctx, index, component)
+			})
+			return err
 		})
 	}
 
 	return g.Wait()
 }
 
-func (a *Anchor) probeComponent(ctx context.Context, component fullComponent) (err error) {
+func (a *Anchor) probeComponent(ctx context.Context, index int, component fullComponent) (err error) {
 	defer func() {
 		if panicErr := recover(); panicErr != nil {
-			a.cfg.logger.ErrorfCtx(ctx, "[anchor] Probe panic for %q: %v", component.Name(), panicErr)
+			a.error(ctx, "[anchor] Probe panic", componentAttr(component), phaseAttr("Probe"), errorAttr(panicErr))
 			err = errors.Join(err, fmt.Errorf("%s", panicErr))
 		}
+		if err != nil && !isCanceled(ctx, err) {
+			a.recordError(component, "Probe", err)
+		}
 	}()
 
 	var attempts int
@@ -193,17 +396,22 @@ func (a *Anchor) probeComponent(ctx context.Context, component fullComponent) (e
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
-			err := component.Probe(ctx)
+			err := a.traced(ctx, "Probe", index, component, component.Probe, Attribute{Key: AttributeAttempt, Value: attempts})
 			if err == nil {
 				return nil
 			}
 
+			a.debug(ctx, "[anchor] Probe failed", componentAttr(component), phaseAttr("Probe"), attemptAttr(attempts), errorAttr(err))
 			backoff, err = a.cfg.readyCheckBackoff(ctx, attempts)
 			if err != nil {
 				return err
 			}
 
-			time.Sleep(backoff)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-a.cfg.clock.After(backoff):
+			}
 		}
 	}
 
@@ -212,13 +420,13 @@ func (a *Anchor) probeComponent(ctx context.Context, component fullComponent) (e
 func (a *Anchor) setupAll(ctx context.Context) int {
 	var cancel context.CancelFunc = func() {}
 	if a.cfg.setupTimeout > 0 {
-		ctx, cancel = context.WithTimeout(ctx, a.cfg.setupTimeout)
+		ctx, cancel = clock.WithTimeout(ctx, a.cfg.clock, a.cfg.setupTimeout)
 	}
 	defer cancel()
 
 	for index := 0; index < len(a.components); index++ {
 		a.setupIndex = index
-		code := a.setupComponent(ctx, a.components[index])
+		code := a.setupComponent(ctx, index, a.components[index])
 		if code != OK {
 			return code
 		}
@@ -227,25 +435,31 @@ func (a *Anchor) setupAll(ctx context.Context) int {
 	return OK
 }
 
-func (a *Anchor) setupComponent(ctx context.Context, component fullComponent) (code int) {
+func (a *Anchor) setupComponent(ctx context.Context, index int, component fullComponent) (code int) {
 
 	done := make(chan int, 1)
 	go func() {
+		began := a.cfg.clock.Now()
 		defer func() {
 			if panicErr := recover(); panicErr != nil {
-				a.cfg.logger.ErrorfCtx(a.cfg.anchorCtx, "[anchor] Setup %q panic: %v", component.Name(), panicErr)
+				a.error(a.cfg.anchorCtx, "[anchor] Setup panic", componentAttr(component), phaseAttr("Setup"), durationAttr(a.since(began)), errorAttr(panicErr))
+				a.recordError(component, "Setup", fmt.Errorf("panic: %v", panicErr))
 				done <- SetupFailed
 			}
 		}()
 
-		err := component.Setup(ctx)
+		var err error
+		a.labeled(ctx, component, "Setup", func(ctx context.Context) {
+			err = a.traced(ctx, "Setup", index, component, component.Setup)
+		})
 		if err != nil {
-			a.cfg.logger.ErrorfCtx(a.cfg.anchorCtx, "[anchor] Setup %q failed: %v", component.Name(), err)
+			a.error(a.cfg.anchorCtx, "[anchor] Setup failed", componentAttr(component), phaseAttr("Setup"), durationAttr(a.since(began)), errorAttr(err))
+			a.recordError(component, "Setup", err)
 			done <- SetupFailed
 			return
 		}
 
-		a.cfg.logger.InfofCtx(a.cfg.anchorCtx, "[anchor] Setup %q", component.Name())
+		a.info(a.cfg.anchorCtx, "[anchor] Setup", componentAttr(component), phaseAttr("Setup"), durationAttr(a.since(began)))
 		done <- OK
 	}()
 
@@ -260,12 +474,12 @@ func (a *Anchor) setupComponent(ctx context.Context, component fullComponent) (c
 
 func (a *Anchor) closeAll(ctx context.Context) int {
 	done := make(chan int, 1)
-	ctx, cancel := context.WithTimeout(ctx, a.cfg.closeTimeout)
+	ctx, cancel := clock.WithTimeout(ctx, a.cfg.clock, a.cfg.closeTimeout)
 	go func() {
 		defer cancel()
 
 		for index := a.setupIndex; index >= 0; index-- {
-			a.closeComponent(ctx, a.components[index])
+			a.closeComponent(ctx, index, a.components[index])
 			a.setupIndex = index
 		}
 
@@ -276,21 +490,32 @@ func (a *Anchor) closeAll(ctx context.Context) int {
 	case code := <-done:
 		return code
 	case <-ctx.Done():
+		a.warn(a.cfg.anchorCtx, "[anchor] Close timeout", durationAttr(a.cfg.closeTimeout))
 		return Interrupted
 	}
 }
 
-func (a *Anchor) closeComponent(ctx context.Context, component fullComponent) {
+func (a *Anchor) closeComponent(ctx context.Context, index int, component fullComponent) {
+	began := a.cfg.clock.Now()
 	defer func() {
 		if panicErr := recover(); panicErr != nil {
-			a.cfg.logger.ErrorfCtx(a.cfg.anchorCtx, "[anchor] Close %q panic: %v", component.Name(), panicErr)
+			a.error(a.cfg.anchorCtx, "[anchor] Close panic", componentAttr(component), phaseAttr("Close"), durationAttr(a.since(began)), errorAttr(panicErr))
+			a.recordError(component, "Close", fmt.Errorf("panic: %v", panicErr))
 		}
 	}()
 
-	err := component.Close(ctx)
+	var err error
+	a.labeled(ctx, component, "Close", func(ctx context.Context) {
+		err = a.traced(ctx, "Close", index, component, component.Close)
+	})
 	if err != nil {
-		a.cfg.logger.ErrorfCtx(a.cfg.anchorCtx, "[anchor] Closed component %s: %v", component.Name(), err)
+		a.error(a.cfg.anchorCtx, "[anchor] Close failed", componentAttr(component), phaseAttr("Close"), durationAttr(a.since(began)), errorAttr(err))
+		a.recordError(component, "Close", err)
 	}
 
-	a.cfg.logger.InfofCtx(a.cfg.anchorCtx, "[anchor] Closed component %s", component.Name())
+	a.info(a.cfg.anchorCtx, "[anchor] Closed component", componentAttr(component), phaseAttr("Close"), durationAttr(a.since(began)))
+}
+
+func (a *Anchor) since(t time.Time) time.Duration {
+	return a.cfg.clock.Now().Sub(t)
 }
@@ -884,4 +884,77 @@ func TestAnchor(t *testing.T) {
 		assert.EqualSlice(t, []string{"setup", "start", "ready", "close"}, calls)
 	})
 
+	t.Run("shutdown when ready", func(t *testing.T) {
+		// arrange
+		var (
+			wg        = &sync.WaitGroup{}
+			component = newComponent("c-0", blockOnStart(time.Minute))
+			wire      = newWire(t, wg)
+			sut       = anchor.New(wire)
+		)
+
+		wg.Add(1)
+		t.Cleanup(wg.Done)
+		sut.Add(component)
+
+		go func() {
+			<-sut.Ready()
+			sut.Shutdown()
+		}()
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.NoError(t, sut.Err())
+		assertCalls(t, component, setupCalled, startCalled, closeCalled)
+	})
+
+	t.Run("shutdown before run", func(t *testing.T) {
+		// arrange
+		var (
+			wg        = &sync.WaitGroup{}
+			component = newComponent("c-0", blockOnStart(time.Minute))
+			wire      = newWire(t, wg)
+			sut       = anchor.New(wire)
+		)
+
+		wg.Add(1)
+		t.Cleanup(wg.Done)
+		sut.Add(component)
+		sut.Shutdown()
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assertCalls(t, component, setupSkipped, startSkipped, closeSkipped)
+	})
+
+	t.Run("report component errors", func(t *testing.T) {
+		// arrange
+		var (
+			wg         = &sync.WaitGroup{}
+			components = []*fullComponentMock{
+				newComponent("c-0", errorOnClose(errors.New("CLOSE"))),
+				newComponent("c-1", errorOnSetup(errors.New("SETUP"))),
+			}
+			wire = newWire(t, wg)
+			sut  = anchor.New(wire)
+		)
+
+		for _, component := range components {
+			wg.Add(1)
+			sut.Add(component)
+		}
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+		assert.Equal(t, "Setup \"c-1\": SETUP\nClose \"c-0\": CLOSE", sut.Err().Error())
+	})
 }
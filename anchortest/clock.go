@@ -0,0 +1,141 @@
+package anchortest
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kyuff/anchor"
+)
+
+var _ anchor.Clock = (*Clock)(nil)
+
+// Clock is a fake anchor.Clock that only moves when Advance is called.
+//
+// Use it with anchor.WithClock to test timeouts and backoff without waiting for them.
+type Clock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*timer
+}
+
+type timer struct {
+	at   time.Time
+	fire func(now time.Time)
+}
+
+// NewClock creates a Clock that starts at now.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the Clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After sends the time on the returned channel when the Clock has advanced by d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.add(d, func(now time.Time) {
+		ch <- now
+	})
+
+	return ch
+}
+
+// AfterFunc calls f in its own goroutine when the Clock has advanced by d.
+func (c *Clock) AfterFunc(d time.Duration, f func()) func() bool {
+	t := c.add(d, func(_ time.Time) {
+		go f()
+	})
+
+	return func() bool {
+		return c.remove(t)
+	}
+}
+
+// Advance moves the Clock forward by d and fires the timers that are due in order.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	due, pending := c.split(c.now)
+	c.timers = pending
+	now := c.now
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	for _, t := range due {
+		t.fire(now)
+	}
+}
+
+// Waiters returns the number of timers waiting for the Clock to advance.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// BlockUntil waits until at least n timers are waiting for the Clock to advance.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *Clock) add(d time.Duration, fire func(now time.Time)) *timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timer{at: c.now.Add(d), fire: fire}
+	if d <= 0 {
+		fire(c.now)
+		return t
+	}
+
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	return t
+}
+
+func (c *Clock) remove(t *timer) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := slices.Index(c.timers, t)
+	if i < 0 {
+		return false
+	}
+
+	c.timers = slices.Delete(c.timers, i, i+1)
+	c.cond.Broadcast()
+	return true
+}
+
+func (c *Clock) split(now time.Time) ([]*timer, []*timer) {
+	var due, pending []*timer
+	for _, t := range c.timers {
+		if t.at.After(now) {
+			pending = append(pending, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+
+	slices.SortStableFunc(due, func(a, b *timer) int {
+		return a.at.Compare(b.at)
+	})
+
+	return due, pending
+}
@@ -0,0 +1,157 @@
+package anchortest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/anchortest"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestClock(t *testing.T) {
+	var (
+		start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	t.Run("fire timers when advanced", func(t *testing.T) {
+		// arrange
+		var (
+			sut    = anchortest.NewClock(start)
+			after  = sut.After(time.Second)
+			called = make(chan struct{})
+		)
+
+		sut.AfterFunc(2*time.Second, func() { close(called) })
+
+		// act
+		sut.Advance(time.Second)
+
+		// assert
+		assert.Equal(t, start.Add(time.Second), <-after)
+		assert.Equal(t, 1, sut.Waiters())
+		sut.Advance(time.Second)
+		<-called
+		assert.Equal(t, 0, sut.Waiters())
+	})
+
+	t.Run("stop timer", func(t *testing.T) {
+		// arrange
+		var (
+			sut  = anchortest.NewClock(start)
+			stop = sut.AfterFunc(time.Second, func() { t.Error("called") })
+		)
+
+		// act
+		stopped := stop()
+
+		// assert
+		assert.Truef(t, stopped, "not stopped")
+		assert.Falsef(t, stop(), "stopped twice")
+		sut.Advance(time.Second)
+	})
+
+	t.Run("interrupt setup on timeout", func(t *testing.T) {
+		// arrange
+		var (
+			clock = anchortest.NewClock(start)
+			code  = make(chan int)
+			sut   = anchor.New(anchor.WireFunc(context.WithCancel),
+				anchor.WithClock(clock),
+				anchor.WithSetupTimeout(time.Hour),
+			).Add(anchor.Make("blocking", func() (*blockingSetup, error) {
+				return &blockingSetup{}, nil
+			}))
+		)
+
+		go func() {
+			code <- sut.Run()
+		}()
+
+		// act
+		clock.BlockUntil(1)
+		clock.Advance(time.Hour)
+
+		// assert
+		assert.Equal(t, anchor.Interrupted, <-code)
+	})
+
+	t.Run("wait for ready check backoff", func(t *testing.T) {
+		// arrange
+		var (
+			clock    = anchortest.NewClock(start)
+			code     = make(chan int)
+			attempts = 0
+			sut      = anchor.New(anchor.WireFunc(context.WithCancel),
+				anchor.WithClock(clock),
+				anchor.WithReadyCheckBackoff(func(ctx context.Context, attempt int) (time.Duration, error) {
+					attempts = attempt
+					if attempt == 3 {
+						return 0, context.DeadlineExceeded
+					}
+					return time.Hour, nil
+				}),
+			).Add(anchor.MakeProbe("not-ready", func() (blocking, error) {
+				return blocking{}, nil
+			}, func(ctx context.Context) error {
+				return errors.New("not ready")
+			}))
+		)
+
+		go func() {
+			code <- sut.Run()
+		}()
+
+		// act
+		for range 2 {
+			clock.BlockUntil(1)
+			clock.Advance(time.Hour)
+		}
+
+		// assert
+		assert.Equal(t, anchor.Internal, <-code)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("time out probes on start timeout", func(t *testing.T) {
+		// arrange
+		var (
+			clock = anchortest.NewClock(start)
+			code  = make(chan int)
+			sut   = anchor.New(anchor.WireFunc(context.WithCancel),
+				anchor.WithClock(clock),
+				anchor.WithStartTimeout(time.Hour),
+				anchor.WithFixedReadyCheckBackoff(time.Hour),
+			).Add(anchor.MakeProbe("not-ready", func() (blocking, error) {
+				return blocking{}, nil
+			}, func(ctx context.Context) error {
+				return errors.New("not ready")
+			}))
+		)
+
+		go func() {
+			code <- sut.Run()
+		}()
+
+		// act
+		clock.BlockUntil(2)
+		clock.Advance(time.Hour)
+
+		// assert
+		assert.Equal(t, anchor.Internal, <-code)
+		assert.Truef(t, errors.Is(sut.Err(), context.DeadlineExceeded), "err: %v", sut.Err())
+	})
+}
+
+type blockingSetup struct{}
+
+func (blockingSetup) Setup(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (blockingSetup) Start(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,246 @@
+package anchortest
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/decorate"
+	"github.com/kyuff/anchor/internal/goroutines"
+)
+
+// Conformance runs a subtest for each part of the contract a Component has with the Anchor.
+//
+// The factory is called for each subtest to get a new Component. The Component must:
+//   - return from Start when the context is canceled
+//   - allow Close without Start
+//   - allow Close to be called twice
+//   - return from Probe when the context is canceled
+//   - not leave goroutines running after Close
+func Conformance(t *testing.T, factory func() anchor.Component, opts ...Option) {
+	t.Helper()
+
+	cfg := applyOptions(defaultOptions(), opts...)
+	for _, check := range checks {
+		t.Run(check.name, func(t *testing.T) {
+			c := &contract{
+				component: decorate.New(factory()),
+				timeout:   cfg.conformanceTimeout,
+				id:        strconv.FormatInt(contractIDs.Add(1), 10),
+			}
+
+			if err := check.fn(t.Context(), c); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+type check struct {
+	name string
+	fn   func(ctx context.Context, c *contract) error
+}
+
+var checks = []check{
+	{name: "start returns when canceled", fn: startReturnsWhenCanceled},
+	{name: "close before start", fn: closeBeforeStart},
+	{name: "close twice", fn: closeTwice},
+	{name: "probe returns when canceled", fn: probeReturnsWhenCanceled},
+	{name: "no goroutines after close", fn: noGoroutinesAfterClose},
+}
+
+// labelContract is the pprof label set on the calls to the Component,
+// so the goroutines it starts can be told apart from the rest of the process.
+const labelContract = "anchortest.contract"
+
+var contractIDs atomic.Int64
+
+// contract calls a Component and fails if it does not return within the timeout.
+type contract struct {
+	component *decorate.Component
+	timeout   time.Duration
+	id        string
+}
+
+// labeled calls fn with the pprof label of the contract.
+func (c *contract) labeled(fn func()) {
+	pprof.Do(context.Background(), pprof.Labels(labelContract, c.id), func(context.Context) {
+		fn()
+	})
+}
+
+// goroutines started by calls to the Component that are still running.
+func (c *contract) goroutines() []goroutines.Group {
+	return goroutines.Labeled(goroutines.Profile(), labelContract, c.id)
+}
+
+func (c *contract) call(phase Phase, fn func() error) (err error) {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if msg := recover(); msg != nil {
+				done <- fmt.Errorf("%s of %q panicked: %v", phase, c.component.Name(), msg)
+			}
+		}()
+
+		c.labeled(func() {
+			done <- fn()
+		})
+	}()
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case err = <-done:
+		if err != nil {
+			return fmt.Errorf("%s of %q failed: %w", phase, c.component.Name(), err)
+		}
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%s of %q did not return within %s", phase, c.component.Name(), c.timeout)
+	}
+}
+
+func (c *contract) setup(ctx context.Context) error {
+	return c.call(PhaseSetup, func() error {
+		return c.component.Setup(ctx)
+	})
+}
+
+func (c *contract) close(ctx context.Context) error {
+	return c.call(PhaseClose, func() error {
+		return c.component.Close(ctx)
+	})
+}
+
+// run the Component until it is ready or the timeout, and return a func that stops it.
+func (c *contract) run(ctx context.Context) (stop func() error) {
+	ctx, cancel := context.WithCancel(ctx)
+	started := make(chan error, 1)
+	go func() {
+		started <- c.call(PhaseStart, func() error {
+			err := c.component.Start(ctx)
+			if ctx.Err() != nil {
+				// errors caused by the cancellation are expected
+				return nil
+			}
+			return err
+		})
+	}()
+
+	deadline := time.Now().Add(c.timeout)
+	for time.Now().Before(deadline) && c.probe(ctx) != nil {
+		time.Sleep(c.timeout / 100)
+	}
+
+	return func() error {
+		cancel()
+		return <-started
+	}
+}
+
+func (c *contract) probe(ctx context.Context) (err error) {
+	c.labeled(func() {
+		err = c.component.Probe(ctx)
+	})
+
+	return err
+}
+
+func startReturnsWhenCanceled(ctx context.Context, c *contract) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	if err := c.run(ctx)(); err != nil {
+		return err
+	}
+
+	return c.close(ctx)
+}
+
+func closeBeforeStart(ctx context.Context, c *contract) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	return c.close(ctx)
+}
+
+func closeTwice(ctx context.Context, c *contract) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	if err := c.run(ctx)(); err != nil {
+		return err
+	}
+
+	if err := c.close(ctx); err != nil {
+		return err
+	}
+
+	return c.close(ctx)
+}
+
+func probeReturnsWhenCanceled(ctx context.Context, c *contract) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	stop := c.run(ctx)
+	defer func() {
+		_ = stop()
+		_ = c.close(ctx)
+	}()
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	err := c.call(PhaseProbe, func() error {
+		_ = c.component.Probe(canceled)
+		return nil
+	})
+
+	return err
+}
+
+func noGoroutinesAfterClose(ctx context.Context, c *contract) error {
+	if err := c.setup(ctx); err != nil {
+		return err
+	}
+
+	if err := c.run(ctx)(); err != nil {
+		return err
+	}
+
+	if err := c.close(ctx); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	for {
+		left := c.goroutines()
+		if len(left) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			var stacks []string
+			for _, g := range left {
+				stacks = append(stacks, fmt.Sprintf("%d @\n%s", g.Count, g.Stack))
+			}
+			return fmt.Errorf("%d goroutines left after Close of %q:\n%s",
+				goroutines.Count(left), c.component.Name(), strings.Join(stacks, "\n\n"))
+		}
+
+		time.Sleep(c.timeout / 100)
+	}
+}
@@ -0,0 +1,121 @@
+package anchortest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+	"github.com/kyuff/anchor/internal/decorate"
+)
+
+// conforming follows the contract of a Component.
+type conforming struct {
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (c *conforming) Start(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+	case <-c.closed:
+	}
+	return nil
+}
+
+func (c *conforming) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+// ignoreCancel does not return from Start when canceled.
+type ignoreCancel struct{}
+
+func (ignoreCancel) Start(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+// closeOnce fails when closed twice.
+type closeOnce struct {
+	closed bool
+}
+
+func (c *closeOnce) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *closeOnce) Close() error {
+	if c.closed {
+		return errors.New("already closed")
+	}
+	c.closed = true
+	return nil
+}
+
+// blockingProbe ignores the context in Probe.
+type blockingProbe struct{}
+
+func (blockingProbe) Start(ctx context.Context) error {
+	return nil
+}
+
+func (blockingProbe) Probe(ctx context.Context) error {
+	time.Sleep(time.Second)
+	return nil
+}
+
+// leaking leaves a goroutine running after Close.
+type leaking struct{}
+
+func (leaking) Start(ctx context.Context) error {
+	go time.Sleep(time.Second)
+	return nil
+}
+
+func TestConformance(t *testing.T) {
+	t.Run("pass conforming component", func(t *testing.T) {
+		Conformance(t, func() anchor.Component {
+			return &conforming{closed: make(chan struct{})}
+		})
+	})
+
+	testCases := []struct {
+		name      string
+		check     string
+		component anchor.Component
+	}{
+		{name: "fail when start ignores cancel", check: "start returns when canceled", component: ignoreCancel{}},
+		{name: "fail when close is not idempotent", check: "close twice", component: &closeOnce{}},
+		{name: "fail when probe blocks", check: "probe returns when canceled", component: blockingProbe{}},
+		{name: "fail when goroutines leak", check: "no goroutines after close", component: leaking{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			var (
+				c = &contract{
+					component: decorate.New(tc.component),
+					timeout:   100 * time.Millisecond,
+				}
+				fn func(ctx context.Context, c *contract) error
+			)
+
+			for _, check := range checks {
+				if check.name == tc.check {
+					fn = check.fn
+				}
+			}
+
+			// act
+			err := fn(t.Context(), c)
+
+			// assert
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package anchortest contains helpers for testing applications built with an Anchor.
+package anchortest
@@ -0,0 +1,216 @@
+package anchortest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/clock"
+	"github.com/kyuff/anchor/internal/decorate"
+)
+
+// ErrFault is returned by faults that are not given an error.
+var ErrFault = errors.New("anchortest: injected fault")
+
+// Phase of the Component lifecycle.
+type Phase string
+
+const (
+	PhaseSetup Phase = "setup"
+	PhaseStart Phase = "start"
+	PhaseProbe Phase = "probe"
+	PhaseClose Phase = "close"
+)
+
+// Fault changes the behaviour of a Component wrapped by Faulty.
+type Fault func(f *faulty)
+
+// SetupError makes Setup return err without calling the Component.
+func SetupError(err error) Fault {
+	return func(f *faulty) {
+		f.setupErr = err
+	}
+}
+
+// StartError makes Start return err after the duration, measured by the anchor.Clock.
+// The Component is started and keeps running until the Anchor closes.
+func StartError(err error, after time.Duration) Fault {
+	return func(f *faulty) {
+		f.startErr = err
+		f.startAfter = after
+	}
+}
+
+// ProbeFailures makes the first n calls to Probe fail.
+func ProbeFailures(n int) Fault {
+	return func(f *faulty) {
+		f.probeFailures = int64(n)
+	}
+}
+
+// CloseHang makes Close block until its context is done.
+func CloseHang() Fault {
+	return func(f *faulty) {
+		f.closeHang = true
+	}
+}
+
+// Panic makes the Component panic in the phase.
+func Panic(phase Phase) Fault {
+	return func(f *faulty) {
+		f.panics = append(f.panics, phase)
+	}
+}
+
+// FaultsFromEnv reads faults from the environment variable key, so they can be
+// turned on without changing the code. The value is a comma separated list of:
+//
+//	setup-error
+//	start-error:<duration>
+//	probe-failures:<n>
+//	close-hang
+//	panic:<setup|start|probe|close>
+//
+// Errors are ErrFault. A malformed value makes Setup fail.
+func FaultsFromEnv(key string) Fault {
+	return func(f *faulty) {
+		faults, err := parseFaults(os.Getenv(key))
+		if err != nil {
+			f.setupErr = fmt.Errorf("%s: %w", key, err)
+			return
+		}
+
+		for _, fault := range faults {
+			fault(f)
+		}
+	}
+}
+
+// Faulty wraps the Component and injects the faults into its lifecycle.
+// It is used to verify how an application behaves when a Component fails.
+func Faulty(component anchor.Component, faults ...Fault) anchor.Component {
+	f := &faulty{inner: decorate.New(component)}
+	for _, fault := range faults {
+		fault(f)
+	}
+
+	return f
+}
+
+type faulty struct {
+	inner *decorate.Component
+
+	setupErr      error
+	startErr      error
+	startAfter    time.Duration
+	probeFailures int64
+	probeAttempts atomic.Int64
+	closeHang     bool
+	panics        []Phase
+}
+
+func (f *faulty) Name() string {
+	return f.inner.Name()
+}
+
+func (f *faulty) Setup(ctx context.Context) error {
+	f.panicIn(PhaseSetup)
+	if f.setupErr != nil {
+		return f.setupErr
+	}
+
+	return f.inner.Setup(ctx)
+}
+
+func (f *faulty) Start(ctx context.Context) error {
+	f.panicIn(PhaseStart)
+	if f.startErr == nil {
+		return f.inner.Start(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f.inner.Start(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-clock.FromContext(ctx).After(f.startAfter):
+		return f.startErr
+	}
+}
+
+func (f *faulty) Probe(ctx context.Context) error {
+	f.panicIn(PhaseProbe)
+	if f.probeAttempts.Add(1) <= f.probeFailures {
+		return ErrFault
+	}
+
+	return f.inner.Probe(ctx)
+}
+
+func (f *faulty) Close(ctx context.Context) error {
+	f.panicIn(PhaseClose)
+	if f.closeHang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	return f.inner.Close(ctx)
+}
+
+func (f *faulty) panicIn(phase Phase) {
+	for _, p := range f.panics {
+		if p == phase {
+			panic(fmt.Sprintf("anchortest: injected panic in %s of %s", phase, f.Name()))
+		}
+	}
+}
+
+func parseFaults(spec string) ([]Fault, error) {
+	var faults []Fault
+	for item := range strings.SplitSeq(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, arg, _ := strings.Cut(item, ":")
+		switch name {
+		case "setup-error":
+			faults = append(faults, SetupError(ErrFault))
+		case "start-error":
+			after, err := time.ParseDuration(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fault %q: %w", item, err)
+			}
+			faults = append(faults, StartError(ErrFault, after))
+		case "probe-failures":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fault %q: %w", item, err)
+			}
+			faults = append(faults, ProbeFailures(n))
+		case "close-hang":
+			faults = append(faults, CloseHang())
+		case "panic":
+			switch phase := Phase(arg); phase {
+			case PhaseSetup, PhaseStart, PhaseProbe, PhaseClose:
+				faults = append(faults, Panic(phase))
+			default:
+				return nil, fmt.Errorf("invalid fault %q: unknown phase", item)
+			}
+		default:
+			return nil, fmt.Errorf("unknown fault %q", item)
+		}
+	}
+
+	return faults, nil
+}
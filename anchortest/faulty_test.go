@@ -0,0 +1,117 @@
+package anchortest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/anchortest"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestFaulty(t *testing.T) {
+	var (
+		run = func(t *testing.T, component anchor.Component, opts ...anchor.Option) int {
+			t.Helper()
+			a := anchor.New(anchor.WireFunc(context.WithCancel), opts...).Add(component)
+			go func() {
+				select {
+				case <-a.Ready():
+					a.Shutdown()
+				case <-t.Context().Done():
+				}
+			}()
+
+			return a.Run()
+		}
+	)
+
+	testCases := []struct {
+		name     string
+		faults   []anchortest.Fault
+		opts     []anchor.Option
+		env      string
+		expected int
+	}{
+		{
+			name:     "no faults",
+			expected: anchor.OK,
+		},
+		{
+			name:     "setup error",
+			faults:   []anchortest.Fault{anchortest.SetupError(errors.New("FAIL"))},
+			expected: anchor.SetupFailed,
+		},
+		{
+			name:     "start error",
+			faults:   []anchortest.Fault{anchortest.StartError(errors.New("FAIL"), 0)},
+			expected: anchor.Internal,
+		},
+		{
+			name:     "probe failures",
+			faults:   []anchortest.Fault{anchortest.ProbeFailures(2)},
+			opts:     []anchor.Option{anchor.WithFixedReadyCheckBackoff(time.Millisecond)},
+			expected: anchor.OK,
+		},
+		{
+			name:     "close hang",
+			faults:   []anchortest.Fault{anchortest.CloseHang()},
+			opts:     []anchor.Option{anchor.WithCloseTimeout(10 * time.Millisecond)},
+			expected: anchor.Interrupted,
+		},
+		{
+			name:     "panic in setup",
+			faults:   []anchortest.Fault{anchortest.Panic(anchortest.PhaseSetup)},
+			expected: anchor.SetupFailed,
+		},
+		{
+			name:     "panic in start",
+			faults:   []anchortest.Fault{anchortest.Panic(anchortest.PhaseStart)},
+			expected: anchor.Internal,
+		},
+		{
+			name:     "panic in probe",
+			faults:   []anchortest.Fault{anchortest.Panic(anchortest.PhaseProbe)},
+			expected: anchor.Internal,
+		},
+		{
+			name:     "faults from env",
+			env:      "probe-failures:1, start-error:0s",
+			expected: anchor.Internal,
+		},
+		{
+			name:     "malformed faults from env",
+			env:      "probe-failures:many",
+			expected: anchor.SetupFailed,
+		},
+		{
+			name:     "unknown fault from env",
+			env:      "panic:later",
+			expected: anchor.SetupFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			var (
+				faults = tc.faults
+			)
+
+			if tc.env != "" {
+				t.Setenv("ANCHORTEST_FAULTS", tc.env)
+				faults = append(faults, anchortest.FaultsFromEnv("ANCHORTEST_FAULTS"))
+			}
+
+			sut := anchortest.Faulty(blocking{}, faults...)
+
+			// act
+			code := run(t, sut, tc.opts...)
+
+			// assert
+			assert.Equal(t, tc.expected, code)
+		})
+	}
+}
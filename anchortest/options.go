@@ -0,0 +1,57 @@
+package anchortest
+
+import "time"
+
+type config struct {
+	readyTimeout       time.Duration
+	conformanceTimeout time.Duration
+	leakGrace          time.Duration
+}
+
+func defaultOptions() *config {
+	return applyOptions(&config{},
+		// add default options here
+		WithReadyTimeout(30*time.Second),
+		WithConformanceTimeout(5*time.Second),
+	)
+}
+
+func applyOptions(options *config, opts ...Option) *config {
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return options
+}
+
+// Option for the test helpers.
+type Option func(cfg *config)
+
+// WithReadyTimeout fails the test if the Anchor is not ready within the timeout.
+//
+// Default: 30 seconds
+func WithReadyTimeout(timeout time.Duration) Option {
+	return func(cfg *config) {
+		cfg.readyTimeout = timeout
+	}
+}
+
+// WithConformanceTimeout is the time a Component has to return from each call
+// in a Conformance test.
+//
+// Default: 5 seconds
+func WithConformanceTimeout(timeout time.Duration) Option {
+	return func(cfg *config) {
+		cfg.conformanceTimeout = timeout
+	}
+}
+
+// WithLeakCheck makes Run fail the test if Components leave goroutines
+// running for longer than grace after the Anchor closed.
+//
+// Default: No check
+func WithLeakCheck(grace time.Duration) Option {
+	return func(cfg *config) {
+		cfg.leakGrace = grace
+	}
+}
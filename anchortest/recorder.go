@@ -0,0 +1,242 @@
+package anchortest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/clock"
+	"github.com/kyuff/anchor/internal/decorate"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden write the golden file.
+const UpdateGoldenEnv = "ANCHORTEST_UPDATE_GOLDEN"
+
+// Call is a lifecycle method called on a Component.
+type Call struct {
+	Component string
+	Phase     Phase
+	// Begin and End of the call, measured by the anchor.Clock.
+	Begin time.Time
+	End   time.Time
+	// Err returned by the call.
+	Err error
+}
+
+func (c Call) String() string {
+	return fmt.Sprintf("%s %s", c.Phase, c.Component)
+}
+
+// Recorder records the lifecycle calls on the Components it wraps, in the order they are made.
+//
+// Start and Probe are called concurrently for all Components, so only Setup and Close
+// follow a strict order across Components.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []*Call
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Wrap the Component, so calls to it are recorded.
+func (r *Recorder) Wrap(component anchor.Component) anchor.Component {
+	return &recorded{
+		recorder: r,
+		inner:    decorate.New(component),
+	}
+}
+
+// WrapAll wraps each of the Components.
+func (r *Recorder) WrapAll(components ...anchor.Component) []anchor.Component {
+	var wrapped = make([]anchor.Component, 0, len(components))
+	for _, component := range components {
+		wrapped = append(wrapped, r.Wrap(component))
+	}
+
+	return wrapped
+}
+
+// Calls returns the recorded calls in the order they began.
+func (r *Recorder) Calls(phases ...Phase) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, call := range r.calls {
+		if len(phases) == 0 || slices.Contains(phases, call.Phase) {
+			calls = append(calls, *call)
+		}
+	}
+
+	return calls
+}
+
+// AssertOrder fails the test if the expected calls are not recorded in the given order.
+// A call is written as "<phase> <component>", like "setup database". Other calls may be
+// recorded in between the expected ones.
+func (r *Recorder) AssertOrder(t testing.TB, expected ...string) bool {
+	t.Helper()
+
+	var (
+		calls = r.Calls()
+		next  = 0
+	)
+	for _, call := range calls {
+		if next < len(expected) && call.String() == expected[next] {
+			next++
+		}
+	}
+
+	if next < len(expected) {
+		t.Errorf("call %q was not recorded in order\nExpected: %s\nRecorded: %s",
+			expected[next], strings.Join(expected, ", "), joinCalls(calls))
+		return false
+	}
+
+	return true
+}
+
+// AssertClosedInReverse fails the test if the Components were not closed in the
+// reverse order of which they were setup.
+func (r *Recorder) AssertClosedInReverse(t testing.TB) bool {
+	t.Helper()
+
+	var (
+		setup  = names(r.Calls(PhaseSetup))
+		closed = names(r.Calls(PhaseClose))
+	)
+
+	slices.Reverse(setup)
+	if !slices.Equal(setup, closed) {
+		t.Errorf("components were not closed in reverse order of setup\nExpected: %s\nClosed:   %s",
+			strings.Join(setup, ", "), strings.Join(closed, ", "))
+		return false
+	}
+
+	return true
+}
+
+// AssertGolden fails the test if the calls in the phases differ from the golden file.
+// If no phases are given, Setup and Close are used as they have a strict order.
+//
+// Set the environment variable ANCHORTEST_UPDATE_GOLDEN=1 to write the file.
+func (r *Recorder) AssertGolden(t testing.TB, file string, phases ...Phase) bool {
+	t.Helper()
+
+	if len(phases) == 0 {
+		phases = []Phase{PhaseSetup, PhaseClose}
+	}
+
+	var sb strings.Builder
+	for _, call := range r.Calls(phases...) {
+		sb.WriteString(call.String())
+		sb.WriteString("\n")
+	}
+	got := sb.String()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Errorf("create golden dir: %v", err)
+			return false
+		}
+		if err := os.WriteFile(file, []byte(got), 0o644); err != nil {
+			t.Errorf("write golden file: %v", err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(file)
+	if err != nil {
+		t.Errorf("read golden file (set %s=1 to create it): %v", UpdateGoldenEnv, err)
+		return false
+	}
+
+	if string(expected) != got {
+		t.Errorf("calls differ from golden file %s\nExpected:\n%s\nRecorded:\n%s", file, expected, got)
+		return false
+	}
+
+	return true
+}
+
+func (r *Recorder) record(ctx context.Context, name string, phase Phase, fn func() error) error {
+	var (
+		c    = clock.FromContext(ctx)
+		call = &Call{Component: name, Phase: phase, Begin: c.Now()}
+	)
+
+	r.mu.Lock()
+	r.calls = append(r.calls, call)
+	r.mu.Unlock()
+
+	err := fn()
+
+	r.mu.Lock()
+	call.End = c.Now()
+	call.Err = err
+	r.mu.Unlock()
+
+	return err
+}
+
+type recorded struct {
+	recorder *Recorder
+	inner    *decorate.Component
+}
+
+func (c *recorded) Name() string {
+	return c.inner.Name()
+}
+
+func (c *recorded) Setup(ctx context.Context) error {
+	return c.recorder.record(ctx, c.Name(), PhaseSetup, func() error {
+		return c.inner.Setup(ctx)
+	})
+}
+
+func (c *recorded) Start(ctx context.Context) error {
+	return c.recorder.record(ctx, c.Name(), PhaseStart, func() error {
+		return c.inner.Start(ctx)
+	})
+}
+
+func (c *recorded) Probe(ctx context.Context) error {
+	return c.recorder.record(ctx, c.Name(), PhaseProbe, func() error {
+		return c.inner.Probe(ctx)
+	})
+}
+
+func (c *recorded) Close(ctx context.Context) error {
+	return c.recorder.record(ctx, c.Name(), PhaseClose, func() error {
+		return c.inner.Close(ctx)
+	})
+}
+
+func names(calls []Call) []string {
+	var names = make([]string, 0, len(calls))
+	for _, call := range calls {
+		names = append(names, call.Component)
+	}
+
+	return names
+}
+
+func joinCalls(calls []Call) string {
+	var s = make([]string, 0, len(calls))
+	for _, call := range calls {
+		s = append(s, call.String())
+	}
+
+	return strings.Join(s, ", ")
+}
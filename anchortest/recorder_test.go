@@ -0,0 +1,108 @@
+package anchortest_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/anchortest"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+type named struct {
+	name string
+}
+
+func (c named) Name() string {
+	return c.name
+}
+
+func (c named) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	var (
+		run = func(t *testing.T, sut *anchortest.Recorder) {
+			t.Helper()
+			a := anchor.New(anchor.WireFunc(context.WithCancel)).
+				Add(sut.WrapAll(named{"a"}, named{"b"}, named{"c"})...)
+			go func() {
+				<-a.Ready()
+				a.Shutdown()
+			}()
+			assert.Equal(t, anchor.OK, a.Run())
+		}
+	)
+
+	t.Run("record calls in order", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchortest.NewRecorder()
+		)
+
+		// act
+		run(t, sut)
+
+		// assert
+		sut.AssertOrder(t, "setup a", "setup b", "setup c", "start a", "probe a", "close c", "close a")
+		sut.AssertClosedInReverse(t)
+		sut.AssertGolden(t, "testdata/recorder.golden")
+		calls := sut.Calls(anchortest.PhaseSetup)
+		assert.Equal(t, 3, len(calls))
+		assert.Truef(t, !calls[0].End.Before(calls[0].Begin), "end before begin")
+	})
+
+	t.Run("fail on wrong order", func(t *testing.T) {
+		// arrange
+		var (
+			sut  = anchortest.NewRecorder()
+			fake = &fakeT{TB: t}
+		)
+
+		run(t, sut)
+
+		// act
+		ok := sut.AssertOrder(fake, "setup b", "setup a")
+
+		// assert
+		assert.Falsef(t, ok, "order accepted")
+		assert.Equal(t, 1, len(fake.failures))
+	})
+
+	t.Run("fail on golden mismatch", func(t *testing.T) {
+		// arrange
+		var (
+			sut  = anchortest.NewRecorder()
+			fake = &fakeT{TB: t}
+		)
+
+		run(t, sut)
+
+		// act
+		ok := sut.AssertGolden(fake, "testdata/recorder.golden", anchortest.PhaseSetup)
+
+		// assert
+		assert.Falsef(t, ok, "golden accepted")
+	})
+
+	t.Run("update golden file", func(t *testing.T) {
+		// arrange
+		var (
+			sut  = anchortest.NewRecorder()
+			file = filepath.Join(t.TempDir(), "update.golden")
+		)
+
+		t.Setenv(anchortest.UpdateGoldenEnv, "1")
+		run(t, sut)
+
+		// act
+		sut.AssertGolden(t, file)
+
+		// assert
+		t.Setenv(anchortest.UpdateGoldenEnv, "")
+		assert.Truef(t, sut.AssertGolden(t, file), "golden not updated")
+	})
+}
@@ -0,0 +1,69 @@
+package anchortest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+)
+
+// NoLeaks fails the test for each goroutine started by a Component of the Anchor, that
+// is still running after grace. It is meant to be called after Run returns.
+func NoLeaks(t testing.TB, a *anchor.Anchor, grace time.Duration) {
+	t.Helper()
+
+	for _, leak := range a.Leaks(grace) {
+		t.Errorf("goroutine leak %s", leak)
+	}
+}
+
+// Run the Anchor in the background of the test and wait until it is ready.
+//
+// The test fails if the Anchor does not become ready within the timeout.
+// When the test ends, the Anchor is shut down and the test fails if Run
+// returned a code other than anchor.OK or any Component returned an error.
+func Run(t testing.TB, a *anchor.Anchor, opts ...Option) {
+	t.Helper()
+
+	var (
+		cfg  = applyOptions(defaultOptions(), opts...)
+		done = make(chan int, 1)
+	)
+
+	go func() {
+		done <- a.Run()
+	}()
+
+	var code = -1
+	t.Cleanup(func() {
+		t.Helper()
+
+		a.Shutdown()
+		if code < 0 {
+			code = <-done
+		}
+
+		if code != anchor.OK {
+			t.Errorf("anchor exited with code %d", code)
+		}
+
+		if err := a.Err(); err != nil {
+			t.Errorf("anchor components failed: %v", err)
+		}
+
+		if cfg.leakGrace > 0 {
+			NoLeaks(t, a, cfg.leakGrace)
+		}
+	})
+
+	timer := time.NewTimer(cfg.readyTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-a.Ready():
+	case code = <-done:
+		t.Fatalf("anchor exited with code %d before it was ready", code)
+	case <-timer.C:
+		t.Fatalf("anchor was not ready within %s", cfg.readyTimeout)
+	}
+}
@@ -0,0 +1,179 @@
+package anchortest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/anchortest"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	mu       sync.Mutex
+	failures []string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...any) {
+	t.Errorf(format, args...)
+}
+
+func (t *fakeT) Cleanup(fn func()) {
+	t.cleanups = append(t.cleanups, fn)
+}
+
+func (t *fakeT) finish() []string {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+
+	return t.failures
+}
+
+type closeFunc func() error
+
+func (fn closeFunc) Close() error {
+	return fn()
+}
+
+type blocking struct{}
+
+func (blocking) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("run until the test ends", func(t *testing.T) {
+		// arrange
+		var (
+			closed = false
+			sut    = anchor.New(anchor.WireFunc(context.WithCancel)).
+				Add(
+					blocking{},
+					anchor.Close("closer", closeFunc(func() error {
+						closed = true
+						return nil
+					})),
+				)
+			fake = &fakeT{TB: t}
+		)
+
+		// act
+		anchortest.Run(fake, sut)
+
+		// assert
+		select {
+		case <-sut.Ready():
+		default:
+			t.Fatal("anchor not ready")
+		}
+		assert.Falsef(t, closed, "closed before the test ended")
+		assert.Equal(t, 0, len(fake.finish()))
+		assert.Truef(t, closed, "not closed when the test ended")
+	})
+
+	t.Run("fail on setup error", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.WireFunc(context.WithCancel)).
+				Add(anchor.Setup("setup", func() error {
+					return errors.New("FAIL")
+				}))
+			fake = &fakeT{TB: t}
+		)
+
+		// act
+		anchortest.Run(fake, sut)
+
+		// assert
+		assert.Equal(t, 3, len(fake.finish()))
+	})
+
+	t.Run("fail on ready timeout", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.WireFunc(context.WithCancel)).
+				Add(anchor.MakeProbe("probe", func() (blocking, error) {
+					return blocking{}, nil
+				}, func(ctx context.Context) error {
+					return errors.New("not ready")
+				}))
+			fake = &fakeT{TB: t}
+		)
+
+		// act
+		anchortest.Run(fake, sut, anchortest.WithReadyTimeout(50*time.Millisecond))
+
+		// assert
+		assert.Equal(t, 1, len(fake.failures))
+		fake.finish()
+	})
+
+	t.Run("fail on close error", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.WireFunc(context.WithCancel)).
+				Add(
+					blocking{},
+					anchor.Close("closer", closeFunc(func() error {
+						return errors.New("FAIL")
+					})),
+				)
+			fake = &fakeT{TB: t}
+		)
+
+		// act
+		anchortest.Run(fake, sut)
+
+		// assert
+		assert.Equal(t, 1, len(fake.finish()))
+	})
+}
+
+type leaking struct {
+	release chan struct{}
+}
+
+func (c *leaking) Start(ctx context.Context) error {
+	go func() {
+		<-c.release
+	}()
+
+	<-ctx.Done()
+	return nil
+}
+
+func TestNoLeaks(t *testing.T) {
+	t.Run("fail on leaked goroutines", func(t *testing.T) {
+		// arrange
+		var (
+			component = &leaking{release: make(chan struct{})}
+			sut       = anchor.New(anchor.WireFunc(context.WithCancel)).Add(component)
+			fake      = &fakeT{TB: t}
+		)
+
+		t.Cleanup(func() { close(component.release) })
+
+		// act
+		anchortest.Run(fake, sut, anchortest.WithLeakCheck(10*time.Millisecond))
+
+		// assert
+		assert.Equal(t, 1, len(fake.finish()))
+	})
+}
@@ -0,0 +1,61 @@
+package anchor
+
+import (
+	"context"
+
+	"github.com/kyuff/anchor/internal/decorate"
+)
+
+// Builder of a Component from functions. It is made by Build.
+type Builder struct {
+	name  string
+	setup func(ctx context.Context) error
+	start func(ctx context.Context) error
+	probe func(ctx context.Context) error
+	close func(ctx context.Context) error
+}
+
+// Build a Component from functions for each of its phases. A phase that is not given does nothing.
+//
+//	anchor.Build("db").
+//		Setup(connect).
+//		Start(serve).
+//		Close(disconnect).
+//		Component()
+func Build(name string) *Builder {
+	return &Builder{name: name}
+}
+
+// Setup is called before Start, with the Deadline of the setup timeout.
+func (b *Builder) Setup(fn func(ctx context.Context) error) *Builder {
+	b.setup = fn
+	return b
+}
+
+// Start is called to run the Component until ctx is done.
+func (b *Builder) Start(fn func(ctx context.Context) error) *Builder {
+	b.start = fn
+	return b
+}
+
+// Probe is called after Start until it returns nil, to check if the Component is ready.
+func (b *Builder) Probe(fn func(ctx context.Context) error) *Builder {
+	b.probe = fn
+	return b
+}
+
+// Close is called after Start, with the Deadline of the close timeout.
+func (b *Builder) Close(fn func(ctx context.Context) error) *Builder {
+	b.close = fn
+	return b
+}
+
+// Component with the functions given to the Builder.
+func (b *Builder) Component() Component {
+	return decorate.Funcs(b.name, b.setup, b.start, b.probe, b.close)
+}
+
+// Func creates a Component that only Starts, e.g. a background loop that runs until ctx is done.
+func Func(name string, fn func(ctx context.Context) error) Component {
+	return decorate.Funcs(name, nil, fn, nil, nil)
+}
@@ -0,0 +1,133 @@
+package anchor_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestBuild(t *testing.T) {
+	var (
+		runUntilReady = func(components ...anchor.Component) int {
+			wire := anchor.ManualWire()
+			return anchor.New(wire,
+				anchor.WithFixedReadyCheckBackoff(time.Millisecond),
+				anchor.WithReadyCallback(func(ctx context.Context) error {
+					wire.Stop(nil)
+					return nil
+				}),
+			).Add(components...).Run()
+		}
+	)
+
+	t.Run("run the lifecycle", func(t *testing.T) {
+		// arrange
+		var (
+			mu     sync.Mutex
+			called []string
+			record = func(phase string) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					mu.Lock()
+					called = append(called, phase)
+					mu.Unlock()
+					return nil
+				}
+			}
+			sut = anchor.Build("TEST NAME").
+				Setup(record("setup")).
+				Start(func(ctx context.Context) error {
+					_ = record("start")(ctx)
+					<-ctx.Done()
+					return nil
+				}).
+				Probe(record("probe")).
+				Close(record("close")).
+				Component()
+		)
+
+		// act
+		code := runUntilReady(sut)
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, "setup", called[0])
+		assert.Equal(t, "close", called[len(called)-1])
+		assert.Truef(t, slices.Contains(called, "start"), "start not called: %v", called)
+		assert.Truef(t, slices.Contains(called, "probe"), "probe not called: %v", called)
+		component, ok := sut.(interface{ Name() string })
+		if assert.Truef(t, ok, "expected Name() method") {
+			assert.Equal(t, "TEST NAME", component.Name())
+		}
+	})
+
+	t.Run("fail setup", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.Build("TEST NAME").
+				Setup(func(ctx context.Context) error { return errors.New("fail") }).
+				Component()
+		)
+
+		// act
+		code := runUntilReady(sut)
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+	})
+
+	t.Run("do nothing for phases not given", func(t *testing.T) {
+		// act
+		code := runUntilReady(anchor.Build("TEST NAME").Component())
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+	})
+}
+
+func TestFunc(t *testing.T) {
+	t.Run("run until the context is done", func(t *testing.T) {
+		// arrange
+		var (
+			wire    = anchor.ManualWire()
+			stopped = make(chan struct{})
+			sut     = anchor.Func("TEST NAME", func(ctx context.Context) error {
+				<-ctx.Done()
+				close(stopped)
+				return nil
+			})
+		)
+
+		// act
+		code := anchor.New(wire, anchor.WithReadyCallback(func(ctx context.Context) error {
+			wire.Stop(nil)
+			return nil
+		})).Add(sut).Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		<-stopped
+	})
+
+	t.Run("fail the anchor", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.Func("TEST NAME", func(ctx context.Context) error {
+				return errors.New("fail")
+			})
+		)
+
+		// act
+		code := anchor.New(anchor.ManualWire()).Add(sut).Run()
+
+		// assert
+		assert.Equal(t, anchor.Internal, code)
+	})
+}
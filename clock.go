@@ -0,0 +1,24 @@
+package anchor
+
+import (
+	"time"
+
+	"github.com/kyuff/anchor/internal/clock"
+)
+
+// Clock measures the time used by the Anchor for timeouts, ready check backoff
+// and the delay before a Component is probed.
+//
+// It is passed on to Components in the contexts given to them.
+// A fake Clock for tests is in package anchortest.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+	// AfterFunc calls f in its own goroutine after the duration has elapsed.
+	// The returned func stops the call and reports whether it did so.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+var _ clock.Clock = Clock(nil)
@@ -3,10 +3,14 @@ package anchor
 import (
 	"context"
 	"time"
+
+	"github.com/kyuff/anchor/internal/clock"
 )
 
 type config struct {
-	logger Logger
+	logger StructuredLogger
+	tracer Tracer
+	clock  Clock
 	// anchorCtx is used to derive the setup, start and close contexts.
 	anchorCtx         context.Context
 	setupTimeout      time.Duration
@@ -14,12 +18,22 @@ type config struct {
 	closeTimeout      time.Duration
 	onReady           func(ctx context.Context) error
 	readyCheckBackoff func(ctx context.Context, attempt int) (time.Duration, error)
+	leakGrace         time.Duration
+	observers         []observer
+	timelineWidth     int
+
+	// readyCheckBackoffDescription of the option that set readyCheckBackoff
+	readyCheckBackoffDescription string
+	// unattributedLeaks reports goroutines without labels as leaks
+	unattributedLeaks bool
 }
 
 func defaultOptions() *config {
 	return applyOptions(&config{},
 		// add default options here
 		WithNoopLogger(),
+		WithTracer(noopTracer{}),
+		WithClock(clock.Real{}),
 		WithAnchorContext(context.Background()),
 		WithSetupTimeout(0), // no timeout
 		WithStartTimeout(0), // no timeout
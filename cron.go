@@ -0,0 +1,144 @@
+package anchor
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyuff/anchor/internal/clock"
+	"github.com/kyuff/anchor/internal/cron"
+)
+
+// CronOption for a Component made by Cron.
+type CronOption func(cfg *cronConfig)
+
+type cronConfig struct {
+	jobConfig
+	location *time.Location
+}
+
+// WithCronLocation sets the time zone of the schedule.
+// A CRON_TZ= prefix in the expression takes precedence.
+//
+// Default: time.Local
+func WithCronLocation(loc *time.Location) CronOption {
+	return func(cfg *cronConfig) {
+		cfg.location = loc
+	}
+}
+
+// WithCronOverlap sets what happens when a run is due while the previous run is not done.
+//
+// Default: OverlapSkip
+func WithCronOverlap(overlap Overlap) CronOption {
+	return func(cfg *cronConfig) {
+		cfg.overlap = overlap
+	}
+}
+
+// WithCronTimeout cancels the context of a run after d.
+//
+// Default: No timeout
+func WithCronTimeout(d time.Duration) CronOption {
+	return func(cfg *cronConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithCronOnError is called with the error of each run that fails, e.g. to log it.
+//
+// Default: the error is only counted
+func WithCronOnError(fn func(ctx context.Context, err error)) CronOption {
+	return func(cfg *cronConfig) {
+		cfg.onError = fn
+	}
+}
+
+// WithCronFailAfter fails the Anchor when n runs in a row return an error.
+//
+// Default: the Anchor does not fail
+func WithCronFailAfter(n int) CronOption {
+	return func(cfg *cronConfig) {
+		cfg.failAfter = n
+	}
+}
+
+// Cron creates a Component that calls fn when the cron expression matches, while it is Started.
+//
+// The expression has 5 fields (minute, hour, day of month, month, day of week) or 6 fields
+// with seconds first, e.g. "0 */5 * * * *" for every five minutes. Fields can be lists,
+// ranges, steps and names like MON-FRI. The macros @yearly, @monthly, @weekly, @daily and @hourly
+// are supported, and a time zone can be given as a prefix like "CRON_TZ=Europe/Copenhagen 0 9 * * *".
+//
+// An invalid expression fails the Setup of the Component. When the Anchor closes,
+// no new runs are made and Close waits for those in flight.
+func Cron(name, expr string, fn func(ctx context.Context) error, opts ...CronOption) *CronComponent {
+	cfg := &cronConfig{
+		jobConfig: defaultJobConfig(),
+		location:  time.Local,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	schedule, err := cron.Parse(expr)
+	c := &CronComponent{
+		name:     name,
+		location: cfg.location,
+		schedule: schedule,
+		err:      err,
+	}
+	c.job = newJob(fn, &cfg.jobConfig, func(_, now time.Time) time.Time {
+		return c.next(now)
+	})
+
+	return c
+}
+
+// CronComponent runs a job on a cron schedule. It is made by Cron.
+type CronComponent struct {
+	name     string
+	location *time.Location
+	schedule *cron.Schedule
+	err      error
+	job      *job
+}
+
+// Name of the Component.
+func (c *CronComponent) Name() string {
+	return c.name
+}
+
+// Runs is the number of runs that are done.
+func (c *CronComponent) Runs() int {
+	runs, _ := c.job.counts()
+	return runs
+}
+
+// Errors is the number of runs that returned an error.
+func (c *CronComponent) Errors() int {
+	_, errors := c.job.counts()
+	return errors
+}
+
+// Setup fails if the cron expression is invalid.
+func (c *CronComponent) Setup(ctx context.Context) error {
+	return c.err
+}
+
+// Start runs the job on the schedule until ctx is done.
+func (c *CronComponent) Start(ctx context.Context) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.job.start(ctx, c.next(clock.FromContext(ctx).Now()))
+}
+
+// Close waits for the runs in flight. If ctx is done first, their context is cancelled.
+func (c *CronComponent) Close(ctx context.Context) error {
+	return c.job.close(ctx)
+}
+
+func (c *CronComponent) next(now time.Time) time.Time {
+	return c.schedule.Next(now.In(c.location))
+}
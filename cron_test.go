@@ -0,0 +1,129 @@
+package anchor_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/anchortest"
+	"github.com/kyuff/anchor/internal/assert"
+	"github.com/kyuff/anchor/internal/clock"
+)
+
+func TestCron(t *testing.T) {
+	var (
+		start = func(t *testing.T, sut *anchor.CronComponent, now time.Time) (*anchortest.Clock, context.CancelFunc, <-chan error) {
+			t.Helper()
+			var (
+				clk         = anchortest.NewClock(now)
+				ctx, cancel = context.WithCancel(clock.NewContext(t.Context(), clk))
+				errs        = make(chan error, 1)
+			)
+			assert.NoError(t, sut.Setup(ctx))
+			go func() { errs <- sut.Start(ctx) }()
+			return clk, cancel, errs
+		}
+		recording = func(calls chan<- time.Time) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				calls <- clock.FromContext(ctx).Now()
+				return nil
+			}
+		}
+	)
+
+	t.Run("run on the schedule", func(t *testing.T) {
+		// arrange
+		var (
+			calls = make(chan time.Time, 10)
+			sut   = anchor.Cron("job", "0 */5 * * * *", recording(calls), anchor.WithCronLocation(time.UTC))
+		)
+		clk, cancel, errs := start(t, sut, time.Date(2025, 1, 1, 10, 3, 0, 0, time.UTC))
+
+		// act
+		clk.BlockUntil(1)
+		clk.Advance(2 * time.Minute)
+		first := <-calls
+		clk.BlockUntil(1)
+		clk.Advance(5 * time.Minute)
+		second := <-calls
+		cancel()
+
+		// assert
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, time.Date(2025, 1, 1, 10, 5, 0, 0, time.UTC), first)
+		assert.Equal(t, time.Date(2025, 1, 1, 10, 10, 0, 0, time.UTC), second)
+		assert.Equal(t, 2, sut.Runs())
+		assert.Equal(t, "job", sut.Name())
+	})
+
+	t.Run("run in the time zone", func(t *testing.T) {
+		// arrange
+		var (
+			copenhagen, _ = time.LoadLocation("Europe/Copenhagen")
+			calls         = make(chan time.Time, 10)
+			sut           = anchor.Cron("job", "0 9 * * *", recording(calls), anchor.WithCronLocation(copenhagen))
+		)
+		clk, cancel, errs := start(t, sut, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		clk.BlockUntil(1)
+
+		// act
+		clk.Advance(8*time.Hour - time.Second)
+		pending := clk.Waiters()
+		clk.Advance(time.Second)
+
+		// assert
+		assert.Equal(t, 1, pending)
+		assert.Equal(t, time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC), <-calls)
+		cancel()
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+	})
+
+	t.Run("wait for the run in flight on close", func(t *testing.T) {
+		// arrange
+		var (
+			calls   = make(chan struct{}, 1)
+			release = make(chan struct{})
+			sut     = anchor.Cron("job", "@hourly", func(ctx context.Context) error {
+				calls <- struct{}{}
+				<-release
+				return nil
+			})
+			closed = make(chan error, 1)
+		)
+		clk, cancel, errs := start(t, sut, time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC))
+		clk.BlockUntil(1)
+		clk.Advance(30 * time.Minute)
+		<-calls
+		cancel()
+		assert.NoError(t, <-errs)
+
+		// act
+		go func() { closed <- sut.Close(t.Context()) }()
+
+		// assert
+		select {
+		case <-closed:
+			t.Fatal("closed before the run was done")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(release)
+		assert.NoError(t, <-closed)
+		assert.Equal(t, 1, sut.Runs())
+	})
+
+	t.Run("fail setup on an invalid expression", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.Cron("job", "61 * * * *", func(ctx context.Context) error { return nil })
+		)
+
+		// act
+		code := anchor.New(anchor.ManualWire()).Add(sut).Run()
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+	})
+}
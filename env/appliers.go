@@ -1,56 +1,105 @@
 package env
 
-func overrideFile(file string) func(kv map[string]string) error {
+import "maps"
+
+// applier changes the values of the environment being built by Set.
+type applier func(e *environment) error
+
+// environment holds the values Set is about to write, together with
+// the Source of each value that was touched by an applier.
+type environment struct {
+	values  map[string]string
+	sources map[string]Source
+}
+
+func newEnvironment(values map[string]string) *environment {
+	return &environment{
+		values:  maps.Clone(values),
+		sources: make(map[string]Source),
+	}
+}
+
+func (e *environment) override(source Source, key, value string) {
+	e.values[key] = value
+	e.sources[key] = source
+}
+
+func (e *environment) fallback(source Source, key, value string) {
+	if _, ok := e.values[key]; ok {
+		if _, touched := e.sources[key]; !touched {
+			e.sources[key] = Source{Applier: "OS"}
+		}
+		return
+	}
+
+	e.values[key] = value
+	e.sources[key] = source
+}
+
+func (e *environment) unset(source Source, key string) {
+	delete(e.values, key)
+	e.sources[key] = source
+}
+
+func overrideFile(name, file string) applier {
 	values, err := readFile(file)
+	return overrideValues(Source{Applier: name, File: file}, values, err)
+}
+
+func overrideValues(source Source, values map[string]string, err error) applier {
 	if err != nil {
-		return func(kv map[string]string) error {
+		return func(e *environment) error {
 			return err
 		}
 	}
 
-	return func(kv map[string]string) error {
+	return func(e *environment) error {
 		for k, v := range values {
-			// cannot fail
-			_ = overrideKeyValue(k, v)(kv)
+			e.override(source, k, v)
 		}
 
 		return nil
 	}
 }
 
-func overrideKeyValue(key string, value string) func(kv map[string]string) error {
-	return func(kv map[string]string) error {
-		kv[key] = value
+func overrideKeyValue(key string, value string) applier {
+	return func(e *environment) error {
+		e.override(Source{Applier: "OverrideKeyValue"}, key, value)
 		return nil
 	}
 }
 
-func defaultFile(file string) func(kv map[string]string) error {
+func defaultFile(name, file string) applier {
 	values, err := readFile(file)
+	return defaultValues(Source{Applier: name, File: file}, values, err)
+}
+
+func defaultValues(source Source, values map[string]string, err error) applier {
 	if err != nil {
-		return func(kv map[string]string) error {
+		return func(e *environment) error {
 			return err
 		}
 	}
 
-	return func(kv map[string]string) error {
+	return func(e *environment) error {
 		for k, v := range values {
-			// cannot fail
-			_ = defaultKeyValue(k, v)(kv)
+			e.fallback(source, k, v)
 		}
 
 		return nil
 	}
 }
 
-func defaultKeyValue(key string, value string) func(kv map[string]string) error {
-	return func(kv map[string]string) error {
-		_, ok := kv[key]
-		if ok {
-			return nil
-		}
+func defaultKeyValue(key string, value string) applier {
+	return func(e *environment) error {
+		e.fallback(Source{Applier: "DefaultKeyValue"}, key, value)
+		return nil
+	}
+}
 
-		kv[key] = value
+func unsetKey(key string) applier {
+	return func(e *environment) error {
+		e.unset(Source{Applier: "Unset"}, key)
 		return nil
 	}
 }
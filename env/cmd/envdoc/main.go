@@ -0,0 +1,83 @@
+// Command envdoc writes documentation of the environment variables read by
+// the configuration structs in a Go package.
+//
+// Usage:
+//
+//	go run github.com/kyuff/anchor/env/cmd/envdoc -dir ./internal/config -markdown CONFIG.md -example .env.example
+//
+// It can be used with go:generate to keep the documentation in sync with the code.
+// See package envdoc for the tags used to describe the variables.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kyuff/anchor/env/envdoc"
+)
+
+func main() {
+	var (
+		dir      = flag.String("dir", ".", "directory of the Go package with the configuration structs")
+		names    = flag.String("types", "", "comma separated list of struct types to document (default all)")
+		markdown = flag.String("markdown", "", "file to write the Markdown table to, - for stdout")
+		example  = flag.String("example", "", "file to write the .env.example to, - for stdout")
+	)
+	flag.Parse()
+
+	if err := run(*dir, *names, *markdown, *example); err != nil {
+		fmt.Fprintf(os.Stderr, "envdoc: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, names, markdown, example string) error {
+	var types []string
+	if names != "" {
+		types = strings.Split(names, ",")
+	}
+
+	vars, err := envdoc.Parse(dir, types...)
+	if err != nil {
+		return err
+	}
+
+	if markdown == "" && example == "" {
+		markdown = "-"
+	}
+
+	if markdown != "" {
+		if err := write(markdown, vars, envdoc.Markdown); err != nil {
+			return err
+		}
+	}
+
+	if example != "" {
+		if err := write(example, vars, envdoc.Example); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func write(file string, vars []envdoc.Variable, fn func(w io.Writer, vars []envdoc.Variable) error) error {
+	if file == "-" {
+		return fn(os.Stdout, vars)
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	err = fn(f, vars)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
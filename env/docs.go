@@ -6,11 +6,30 @@
 // Can either be:
 // - **Override** the current value, no matter if it was there before.
 // - **Default** is used if there where no value already.
+// - **Unset** removes the key.
+//
+// Only keys that change are written to the environment.
+// Snapshot and Restore can be used to undo the changes outside of tests.
 //
 // *Input Types*
 // How is new values read.
 // Can be one of:
 // - **File** path to the file where data is stored ina KEY=value multi-lineformat
+// - **EncryptedFile** like File, but encrypted with AES-GCM using EncryptFile and a KeySource
 // - **EnvKeyFile** Environment key that holds a file path to a file with a values in
 // - **KeyValue** directly pass key and value in as arguments.
+//
+// *Report*
+// SetReport returns where each touched key got its value from, and
+// WithReport or WithReportLog can be used to inspect it from Set.
+// Values of keys matching a secret pattern are masked.
+// Use DryRun to compute the Report without changing the environment.
+//
+// *Scope*
+// A Scope is an environment that is not shared with the process.
+// Use WithScope to Set values in it and NewContext to pass it to components,
+// that read the values with Lookup or Getenv. This allows tests to run in parallel.
+//
+// The variables read by configuration structs can be documented with package envdoc
+// or the envdoc command in env/cmd/envdoc.
 package env
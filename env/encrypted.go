@@ -0,0 +1,179 @@
+package env
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// encryptedHeader starts every encrypted file, so the format can change later.
+const encryptedHeader = "anchor-env:aes-gcm:v1:"
+
+// KeySource reads the key used to encrypt and decrypt env files.
+//
+// A key is 32 random bytes, base64 encoded. Use GenerateKey to create one.
+type KeySource func() ([]byte, error)
+
+// KeyFromEnv reads the key from the environment variable.
+func KeyFromEnv(key string) KeySource {
+	return func() ([]byte, error) {
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			return nil, fmt.Errorf("missing encryption key in %s", key)
+		}
+
+		return decodeKey([]byte(value))
+	}
+}
+
+// KeyFromFile reads the key from the file.
+func KeyFromFile(file string) KeySource {
+	return func() ([]byte, error) {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeKey(b)
+	}
+}
+
+// GenerateKey creates a new random key in the format read by KeyFromEnv and KeyFromFile.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptFile encrypts the env file src and writes it to dst.
+func EncryptFile(src, dst string, key KeySource) error {
+	plain, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	// fail early on files that cannot be read after decryption
+	if _, err = parse(plain); err != nil {
+		return err
+	}
+
+	return writeEncrypted(dst, plain, key)
+}
+
+// DecryptFile decrypts the encrypted env file src and writes it to dst.
+func DecryptFile(src, dst string, key KeySource) error {
+	plain, err := readEncrypted(src, key)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, plain, 0o600)
+}
+
+// RekeyFile encrypts the encrypted env file with a new key.
+func RekeyFile(file string, oldKey, newKey KeySource) error {
+	plain, err := readEncrypted(file, oldKey)
+	if err != nil {
+		return err
+	}
+
+	return writeEncrypted(file, plain, newKey)
+}
+
+func readEncryptedFile(file string, key KeySource) (map[string]string, error) {
+	plain, err := readEncrypted(file, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return parse(plain)
+}
+
+func readEncrypted(file string, key KeySource) ([]byte, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := bytes.CutPrefix(bytes.TrimSpace(b), []byte(encryptedHeader))
+	if !ok {
+		return nil, fmt.Errorf("not an encrypted env file: %s", file)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("malformed encrypted env file %s: %w", file, err)
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed encrypted env file: %s", file)
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(encryptedHeader))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", file, err)
+	}
+
+	return plain, nil
+}
+
+func writeEncrypted(file string, plain []byte, key KeySource) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plain, []byte(encryptedHeader))
+	data := encryptedHeader + base64.StdEncoding.EncodeToString(sealed) + "\n"
+	return os.WriteFile(file, []byte(data), 0o600)
+}
+
+func newAEAD(source KeySource) (cipher.AEAD, error) {
+	if source == nil {
+		return nil, errors.New("missing encryption key source")
+	}
+
+	key, err := source()
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func decodeKey(b []byte) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, fmt.Errorf("malformed encryption key: %w", err)
+	}
+
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	return key, nil
+}
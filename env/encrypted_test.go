@@ -0,0 +1,173 @@
+package env_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kyuff/anchor/env"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestEncryptedFile(t *testing.T) {
+	var (
+		newKey = func(t *testing.T) string {
+			key, err := env.GenerateKey()
+			assert.NoError(t, err)
+			return key
+		}
+		encrypt = func(t *testing.T, key env.KeySource) string {
+			file := filepath.Join(t.TempDir(), "data.env.enc")
+			assert.NoError(t, env.EncryptFile("testdata/data.env", file, key))
+			return file
+		}
+	)
+
+	t.Run("override from encrypted file with key in env", func(t *testing.T) {
+		// arrange
+		var (
+			key = "ENCRYPTED_TEST_KEY"
+		)
+
+		t.Setenv(key, newKey(t))
+		file := encrypt(t, env.KeyFromEnv(key))
+
+		// act
+		report, err := env.SetReport(
+			env.OverrideEncryptedFile(file, env.KeyFromEnv(key)),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1251a", os.Getenv("TEST_KEY_412"))
+		entry, _ := report.Lookup("TEST_KEY_412")
+		assert.Truef(t, entry.Secret, "values from encrypted files are secret")
+	})
+
+	t.Run("default from encrypted file with key in file", func(t *testing.T) {
+		// arrange
+		var (
+			keyFile = filepath.Join(t.TempDir(), "key")
+		)
+
+		assert.NoError(t, os.WriteFile(keyFile, []byte(newKey(t)+"\n"), 0o600))
+		file := encrypt(t, env.KeyFromFile(keyFile))
+		t.Setenv("EXTRA", "")
+		assert.NoError(t, os.Unsetenv("EXTRA"))
+
+		// act
+		err := env.Set(
+			env.DefaultEncryptedFile(file, env.KeyFromFile(keyFile)),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1", os.Getenv("EXTRA"))
+	})
+
+	t.Run("rekey file", func(t *testing.T) {
+		// arrange
+		var (
+			oldKey  = "ENCRYPTED_OLD_KEY"
+			keyFile = filepath.Join(t.TempDir(), "key")
+		)
+
+		t.Setenv(oldKey, newKey(t))
+		assert.NoError(t, os.WriteFile(keyFile, []byte(newKey(t)), 0o600))
+		file := encrypt(t, env.KeyFromEnv(oldKey))
+
+		// act
+		err := env.RekeyFile(file, env.KeyFromEnv(oldKey), env.KeyFromFile(keyFile))
+
+		// assert
+		assert.NoError(t, err)
+		assert.NoError(t, env.Set(env.OverrideEncryptedFile(file, env.KeyFromFile(keyFile)), env.DryRun()))
+		assert.Error(t, env.Set(env.OverrideEncryptedFile(file, env.KeyFromEnv(oldKey)), env.DryRun()))
+	})
+
+	t.Run("decrypt file", func(t *testing.T) {
+		// arrange
+		var (
+			key = "ENCRYPTED_TEST_KEY"
+			dst = filepath.Join(t.TempDir(), "data.env")
+		)
+
+		t.Setenv(key, newKey(t))
+		file := encrypt(t, env.KeyFromEnv(key))
+
+		// act
+		err := env.DecryptFile(file, dst, env.KeyFromEnv(key))
+
+		// assert
+		assert.NoError(t, err)
+		got, _ := os.ReadFile(dst)
+		expected, _ := os.ReadFile("testdata/data.env")
+		assert.Equal(t, string(expected), string(got))
+	})
+
+	t.Run("fail on wrong key", func(t *testing.T) {
+		// arrange
+		var (
+			key = "ENCRYPTED_TEST_KEY"
+		)
+
+		t.Setenv(key, newKey(t))
+		file := encrypt(t, env.KeyFromEnv(key))
+		t.Setenv(key, newKey(t))
+
+		// act
+		err := env.Set(
+			env.OverrideEncryptedFile(file, env.KeyFromEnv(key)),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("fail on missing key", func(t *testing.T) {
+		// act
+		err := env.Set(
+			env.OverrideEncryptedFile("testdata/data.env", env.KeyFromEnv("ENCRYPTED_MISSING_KEY")),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("fail on plain file", func(t *testing.T) {
+		// arrange
+		var (
+			key = "ENCRYPTED_TEST_KEY"
+		)
+
+		t.Setenv(key, newKey(t))
+
+		// act
+		err := env.Set(
+			env.OverrideEncryptedFile("testdata/data.env", env.KeyFromEnv(key)),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("fail to encrypt malformed file", func(t *testing.T) {
+		// arrange
+		var (
+			key = "ENCRYPTED_TEST_KEY"
+		)
+
+		t.Setenv(key, newKey(t))
+
+		// act
+		err := env.EncryptFile("testdata/malformed.env", filepath.Join(t.TempDir(), "out"), env.KeyFromEnv(key))
+
+		// assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+// Package envdoc documents the environment variables read by configuration structs.
+//
+// A configuration struct describes its variables with field tags:
+//
+//	type Config struct {
+//		Addr    string        `env:"HTTP_ADDR,required" description:"Address the server listens on"`
+//		Timeout time.Duration `env:"HTTP_TIMEOUT" default:"5s" description:"Request timeout"`
+//		DB      Database      `envPrefix:"DB_"`
+//	}
+//
+// Nested structs without an env tag are documented as well, with their keys prefixed by envPrefix.
+//
+// The Variables can be found by reflection using Reflect, or from source code using Parse.
+// Markdown and Example writes them as a Markdown table and a .env.example file.
+package envdoc
+
+import "strings"
+
+// Variable is an environment variable read by a configuration struct.
+type Variable struct {
+	Key         string
+	Type        string
+	Default     string
+	Required    bool
+	Description string
+}
+
+type tags struct {
+	env         string
+	envPrefix   string
+	defaults    string
+	description string
+}
+
+func newTags(lookup func(key string) (string, bool)) (tags, bool) {
+	var t tags
+	env, ok := lookup("env")
+	t.env = env
+	t.envPrefix, _ = lookup("envPrefix")
+	t.defaults, _ = lookup("default")
+	t.description, _ = lookup("description")
+	return t, ok && env != "-"
+}
+
+func newVariable(prefix, typ string, t tags) Variable {
+	key, options, _ := strings.Cut(t.env, ",")
+	v := Variable{
+		Key:         prefix + key,
+		Type:        typ,
+		Default:     t.defaults,
+		Description: t.description,
+	}
+
+	for option := range strings.SplitSeq(options, ",") {
+		if strings.TrimSpace(option) == "required" {
+			v.Required = true
+		}
+	}
+
+	return v
+}
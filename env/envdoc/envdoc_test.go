@@ -0,0 +1,127 @@
+package envdoc_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor/env/envdoc"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+type Config struct {
+	Addr     string        `env:"HTTP_ADDR,required" description:"Address the server listens on"`
+	Timeout  time.Duration `env:"HTTP_TIMEOUT" default:"5s" description:"Request timeout"`
+	Database *Database     `envPrefix:"DB_"`
+	Ignored  string        `env:"-"`
+	internal string
+	secret   string `env:"SECRET"`
+}
+
+type Database struct {
+	URL   string   `env:"URL,required" description:"Connection string"`
+	Hosts []string `env:"HOSTS" default:"a,b"`
+}
+
+var expected = []envdoc.Variable{
+	{Key: "HTTP_ADDR", Type: "string", Required: true, Description: "Address the server listens on"},
+	{Key: "HTTP_TIMEOUT", Type: "time.Duration", Default: "5s", Description: "Request timeout"},
+	{Key: "DB_URL", Type: "string", Required: true, Description: "Connection string"},
+	{Key: "DB_HOSTS", Type: "[]string", Default: "a,b"},
+}
+
+func TestReflect(t *testing.T) {
+	t.Run("reflect variables", func(t *testing.T) {
+		// act
+		got, err := envdoc.Reflect(&Config{})
+
+		// assert
+		assert.NoError(t, err)
+		assert.EqualSlice(t, expected, got)
+	})
+
+	t.Run("fail on non struct", func(t *testing.T) {
+		// act
+		_, err := envdoc.Reflect("config")
+
+		// assert
+		assert.Error(t, err)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("parse all structs", func(t *testing.T) {
+		// act
+		got, err := envdoc.Parse("testdata/config")
+
+		// assert
+		assert.NoError(t, err)
+		assert.EqualSlice(t, expected, got)
+	})
+
+	t.Run("parse named struct", func(t *testing.T) {
+		// act
+		got, err := envdoc.Parse("testdata/config", "Database")
+
+		// assert
+		assert.NoError(t, err)
+		assert.EqualSlice(t, []envdoc.Variable{
+			{Key: "URL", Type: "string", Required: true, Description: "Connection string"},
+			{Key: "HOSTS", Type: "[]string", Default: "a,b"},
+		}, got)
+	})
+
+	t.Run("fail on unknown struct", func(t *testing.T) {
+		// act
+		_, err := envdoc.Parse("testdata/config", "Unknown")
+
+		// assert
+		assert.Error(t, err)
+	})
+}
+
+func TestMarkdown(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+
+	// act
+	err := envdoc.Markdown(&buf, expected[:2])
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "| Key | Type | Default | Required | Description |\n"+
+		"|-----|------|---------|----------|-------------|\n"+
+		"| `HTTP_ADDR` | string |  | yes | Address the server listens on |\n"+
+		"| `HTTP_TIMEOUT` | time.Duration | `5s` | no | Request timeout |\n", buf.String())
+}
+
+func TestMarkdownEscape(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+
+	// act
+	err := envdoc.Markdown(&buf, []envdoc.Variable{
+		{Key: "SEPARATOR", Type: "string", Default: "a|b", Description: "Split on | or ,"},
+	})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "| Key | Type | Default | Required | Description |\n"+
+		"|-----|------|---------|----------|-------------|\n"+
+		"| `SEPARATOR` | string | `a\\|b` | no | Split on \\| or , |\n", buf.String())
+}
+
+func TestExample(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+
+	// act
+	err := envdoc.Example(&buf, expected[2:])
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "# Connection string (string, required)\n"+
+		"DB_URL=\n"+
+		"# ([]string)\n"+
+		"DB_HOSTS=a,b\n", buf.String())
+}
@@ -0,0 +1,207 @@
+package envdoc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Parse returns the Variables of the named struct types in the Go package in dir
+// without compiling it. If no names are given, all struct types with env tags that are
+// not nested in another struct are used in the order they are declared.
+func Parse(dir string, names ...string) ([]Variable, error) {
+	structs, order, err := parseStructs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		nested := nestedStructs(structs)
+		for _, name := range order {
+			if !nested[name] && len(parseStruct(structs, "", structs[name])) > 0 {
+				names = append(names, name)
+			}
+		}
+	}
+
+	var vars []Variable
+	for _, name := range names {
+		s, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("envdoc: struct %s not found in %s", name, dir)
+		}
+
+		vars = append(vars, parseStruct(structs, "", s)...)
+	}
+
+	return vars, nil
+}
+
+func parseStructs(dir string) (map[string]*ast.StructType, []string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	slices.Sort(files)
+
+	var (
+		fset    = token.NewFileSet()
+		structs = make(map[string]*ast.StructType)
+		order   []string
+	)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		f, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+
+			if s, ok := spec.Type.(*ast.StructType); ok {
+				structs[spec.Name.Name] = s
+				order = append(order, spec.Name.Name)
+			}
+
+			return false
+		})
+	}
+
+	return structs, order, nil
+}
+
+func nestedStructs(structs map[string]*ast.StructType) map[string]bool {
+	var nested = make(map[string]bool)
+	for _, s := range structs {
+		for _, field := range s.Fields.List {
+			if _, ok := fieldTag(field).Lookup("env"); ok {
+				continue
+			}
+
+			expr := field.Type
+			if star, ok := expr.(*ast.StarExpr); ok {
+				expr = star.X
+			}
+
+			if ident, ok := expr.(*ast.Ident); ok {
+				nested[ident.Name] = true
+			}
+		}
+	}
+
+	return nested
+}
+
+func parseStruct(structs map[string]*ast.StructType, prefix string, s *ast.StructType) []Variable {
+	var vars []Variable
+	for _, field := range s.Fields.List {
+		names := exportedNames(field)
+		if names == 0 {
+			// like Reflect, as the env package does not set unexported fields
+			continue
+		}
+
+		t, ok := newTags(fieldTag(field).Lookup)
+		if ok {
+			typ := types.ExprString(field.Type)
+			for range names {
+				vars = append(vars, newVariable(prefix, typ, t))
+			}
+			continue
+		}
+
+		if t.env == "-" {
+			continue
+		}
+
+		if inner := innerStruct(structs, field.Type); inner != nil {
+			vars = append(vars, parseStruct(structs, prefix+t.envPrefix, inner)...)
+		}
+	}
+
+	return vars
+}
+
+// exportedNames is the number of exported names declared by the field.
+// An embedded field is named by its type.
+func exportedNames(field *ast.Field) int {
+	if len(field.Names) == 0 {
+		if ast.IsExported(embeddedName(field.Type)) {
+			return 1
+		}
+		return 0
+	}
+
+	var n int
+	for _, name := range field.Names {
+		if name.IsExported() {
+			n++
+		}
+	}
+
+	return n
+}
+
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	default:
+		return ""
+	}
+}
+
+func fieldTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+
+	return reflect.StructTag(tag)
+}
+
+func innerStruct(structs map[string]*ast.StructType, expr ast.Expr) *ast.StructType {
+	switch e := expr.(type) {
+	case *ast.StructType:
+		return e
+	case *ast.StarExpr:
+		return innerStruct(structs, e.X)
+	case *ast.Ident:
+		return structs[e.Name]
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,53 @@
+package envdoc
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Reflect returns the Variables of the configuration structs in the order of their fields.
+// A config can be a struct or a pointer to one.
+func Reflect(configs ...any) ([]Variable, error) {
+	var vars []Variable
+	for _, config := range configs {
+		typ := reflect.TypeOf(config)
+		for typ != nil && typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		if typ == nil || typ.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("envdoc: %T is not a struct", config)
+		}
+
+		vars = append(vars, reflectStruct("", typ)...)
+	}
+
+	return vars, nil
+}
+
+func reflectStruct(prefix string, typ reflect.Type) []Variable {
+	var vars []Variable
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		t, ok := newTags(field.Tag.Lookup)
+		if ok {
+			vars = append(vars, newVariable(prefix, field.Type.String(), t))
+			continue
+		}
+
+		inner := field.Type
+		if inner.Kind() == reflect.Pointer {
+			inner = inner.Elem()
+		}
+
+		if inner.Kind() == reflect.Struct && t.env != "-" {
+			vars = append(vars, reflectStruct(prefix+t.envPrefix, inner)...)
+		}
+	}
+
+	return vars
+}
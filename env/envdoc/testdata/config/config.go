@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+type Config struct {
+	Addr     string        `env:"HTTP_ADDR,required" description:"Address the server listens on"`
+	Timeout  time.Duration `env:"HTTP_TIMEOUT" default:"5s" description:"Request timeout"`
+	Database Database      `envPrefix:"DB_"`
+	Ignored  string        `env:"-"`
+	internal string
+	secret   string `env:"SECRET"`
+}
+
+type Database struct {
+	URL   string   `env:"URL,required" description:"Connection string"`
+	Hosts []string `env:"HOSTS" default:"a,b"`
+}
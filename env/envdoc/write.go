@@ -0,0 +1,65 @@
+package envdoc
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Markdown writes the Variables as a Markdown table.
+func Markdown(w io.Writer, vars []Variable) error {
+	var sb strings.Builder
+	sb.WriteString("| Key | Type | Default | Required | Description |\n")
+	sb.WriteString("|-----|------|---------|----------|-------------|\n")
+	for _, v := range vars {
+		var (
+			defaults = ""
+			required = "no"
+		)
+		if v.Default != "" {
+			defaults = fmt.Sprintf("`%s`", markdownEscape(v.Default))
+		}
+		if v.Required {
+			required = "yes"
+		}
+
+		fmt.Fprintf(&sb, "| `%s` | %s | %s | %s | %s |\n",
+			v.Key,
+			markdownEscape(v.Type),
+			defaults,
+			required,
+			markdownEscape(v.Description),
+		)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Example writes the Variables in the dotenv format read by the env package.
+// Each key is set to its default and preceded by a comment with the type and description.
+// Blank lines are not used, as the env package does not accept them.
+func Example(w io.Writer, vars []Variable) error {
+	var sb strings.Builder
+	for _, v := range vars {
+		details := v.Type
+		if v.Required {
+			details += ", required"
+		}
+
+		if v.Description != "" {
+			fmt.Fprintf(&sb, "# %s (%s)\n", v.Description, details)
+		} else {
+			fmt.Fprintf(&sb, "# (%s)\n", details)
+		}
+
+		fmt.Fprintf(&sb, "%s=%s\n", v.Key, v.Default)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
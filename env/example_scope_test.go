@@ -0,0 +1,44 @@
+package env_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/env"
+)
+
+type greeter struct{}
+
+func (greeter) Setup(ctx context.Context) error {
+	fmt.Printf("Hello %s\n", env.Getenv(ctx, "GREETING_NAME"))
+	return nil
+}
+
+func (greeter) Start(ctx context.Context) error {
+	return nil
+}
+
+func ExampleScoped() {
+	ctx, err := env.Scoped(context.Background(),
+		env.OverrideKeyValue("GREETING_NAME", "scope"),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	wire := anchor.WireFunc(func(ctx context.Context) (context.Context, context.CancelFunc) {
+		return context.WithTimeout(ctx, 50*time.Millisecond)
+	})
+
+	code := anchor.New(wire, anchor.WithAnchorContext(ctx)).
+		Add(greeter{}).
+		Run()
+
+	fmt.Printf("Exit code: %d\n", code)
+
+	// Output:
+	// Hello scope
+	// Exit code: 0
+}
@@ -5,13 +5,21 @@ import (
 )
 
 type Config struct {
-	appliers []func(kv map[string]string) error
-	setter   func(key, val string) error
+	appliers  []applier
+	reader    func() map[string]string
+	setter    func(key, val string) error
+	unsetter  func(key string) error
+	reporters []func(report Report)
+	secrets   []string
+	dryRun    bool
 }
 
 func defaultOptions() *Config {
 	return applyOptions(&Config{},
+		withReader(readOS),
 		WithEnvironment(os.Setenv),
+		WithEnvironmentUnset(os.Unsetenv),
+		WithSecretPatterns("*PASSWORD*", "*SECRET*", "*TOKEN*", "*CREDENTIAL*", "*PRIVATE_KEY*", "*API_KEY*"),
 	)
 }
 
@@ -32,23 +40,53 @@ func WithEnvironment(fn func(key, value string) error) Option {
 	}
 }
 
+// WithScope reads and changes the values of the Scope instead of the process environment.
+func WithScope(scope *Scope) Option {
+	return func(cfg *Config) {
+		cfg.reader = scope.environ
+		cfg.setter = scope.Setenv
+		cfg.unsetter = scope.Unsetenv
+	}
+}
+
+func withReader(fn func() map[string]string) Option {
+	return func(cfg *Config) {
+		cfg.reader = fn
+	}
+}
+
+// WithEnvironmentUnset is the counterpart of WithEnvironment used to remove keys.
+func WithEnvironmentUnset(fn func(key string) error) Option {
+	return func(cfg *Config) {
+		cfg.unsetter = fn
+	}
+}
+
+// WithT changes the environment using t.Setenv, so it is restored when the test ends.
+//
+// Only keys that change are set, which allows t.Parallel when Set leaves the environment as is.
 func WithT(t TestingT) Option {
 	return func(cfg *Config) {
 		cfg.setter = func(key, val string) error {
 			t.Setenv(key, val)
 			return nil
 		}
+		cfg.unsetter = func(key string) error {
+			// register the restore of the current value before removing it
+			t.Setenv(key, "")
+			return os.Unsetenv(key)
+		}
 	}
 }
 
 func OverrideFile(file string) Option {
 	return func(cfg *Config) {
-		cfg.appliers = append(cfg.appliers, overrideFile(file))
+		cfg.appliers = append(cfg.appliers, overrideFile("OverrideFile", file))
 	}
 }
 func OverrideEnvKeyFile(key string) Option {
 	return func(cfg *Config) {
-		cfg.appliers = append(cfg.appliers, overrideFile(os.Getenv(key)))
+		cfg.appliers = append(cfg.appliers, overrideFile("OverrideEnvKeyFile", os.Getenv(key)))
 	}
 }
 func OverrideKeyValue(key, value string) Option {
@@ -58,12 +96,12 @@ func OverrideKeyValue(key, value string) Option {
 }
 func DefaultFile(file string) Option {
 	return func(cfg *Config) {
-		cfg.appliers = append(cfg.appliers, defaultFile(file))
+		cfg.appliers = append(cfg.appliers, defaultFile("DefaultFile", file))
 	}
 }
 func DefaultEnvKeyFile(key string) Option {
 	return func(cfg *Config) {
-		cfg.appliers = append(cfg.appliers, defaultFile(os.Getenv(key)))
+		cfg.appliers = append(cfg.appliers, defaultFile("DefaultEnvKeyFile", os.Getenv(key)))
 	}
 }
 func DefaultKeyValue(key, value string) Option {
@@ -71,3 +109,66 @@ func DefaultKeyValue(key, value string) Option {
 		cfg.appliers = append(cfg.appliers, defaultKeyValue(key, value))
 	}
 }
+
+// OverrideEncryptedFile is like OverrideFile for a file encrypted with EncryptFile.
+// Values from the file are masked in the Report.
+func OverrideEncryptedFile(file string, key KeySource) Option {
+	return func(cfg *Config) {
+		values, err := readEncryptedFile(file, key)
+		source := Source{Applier: "OverrideEncryptedFile", File: file, encrypted: true}
+		cfg.appliers = append(cfg.appliers, overrideValues(source, values, err))
+	}
+}
+
+// DefaultEncryptedFile is like DefaultFile for a file encrypted with EncryptFile.
+// Values from the file are masked in the Report.
+func DefaultEncryptedFile(file string, key KeySource) Option {
+	return func(cfg *Config) {
+		values, err := readEncryptedFile(file, key)
+		source := Source{Applier: "DefaultEncryptedFile", File: file, encrypted: true}
+		cfg.appliers = append(cfg.appliers, defaultValues(source, values, err))
+	}
+}
+
+// Unset removes the key from the environment.
+func Unset(key string) Option {
+	return func(cfg *Config) {
+		cfg.appliers = append(cfg.appliers, unsetKey(key))
+	}
+}
+
+// WithReport calls fn with the Report of the keys touched by Set.
+//
+// It can be given multiple times.
+func WithReport(fn func(report Report)) Option {
+	return func(cfg *Config) {
+		cfg.reporters = append(cfg.reporters, fn)
+	}
+}
+
+// WithReportLog logs each Entry of the Report using logf, which
+// fits testing.T.Logf and log.Printf.
+func WithReportLog(logf func(format string, args ...any)) Option {
+	return WithReport(func(report Report) {
+		for _, entry := range report.Entries {
+			logf("%s", entry)
+		}
+	})
+}
+
+// WithSecretPatterns sets the patterns of keys which values are masked in the Report.
+// The patterns are matched case-insensitive using path.Match.
+//
+// Default: *PASSWORD*, *SECRET*, *TOKEN*, *CREDENTIAL*, *PRIVATE_KEY*, *API_KEY*
+func WithSecretPatterns(patterns ...string) Option {
+	return func(cfg *Config) {
+		cfg.secrets = patterns
+	}
+}
+
+// DryRun computes the Report without changing the environment.
+func DryRun() Option {
+	return func(cfg *Config) {
+		cfg.dryRun = true
+	}
+}
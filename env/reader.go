@@ -24,6 +24,10 @@ func readFile(fileName string) (map[string]string, error) {
 		return nil, err
 	}
 
+	return parse(b)
+}
+
+func parse(b []byte) (map[string]string, error) {
 	var kv = make(map[string]string)
 	scanner := bufio.NewScanner(bytes.NewReader(b))
 	for scanner.Scan() {
@@ -0,0 +1,137 @@
+package env
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+const mask = "******"
+
+// Source tells which applier gave a key its value.
+type Source struct {
+	// Applier is the name of the Option that set the value, like OverrideFile or DefaultKeyValue.
+	// It is OS when the value was already present in the environment.
+	Applier string
+	// File is the file the value was read from, if any.
+	File string
+
+	encrypted bool
+}
+
+func (s Source) String() string {
+	if s.File == "" {
+		return s.Applier
+	}
+
+	return fmt.Sprintf("%s(%s)", s.Applier, s.File)
+}
+
+// Entry is the provenance of a single key touched by Set.
+type Entry struct {
+	Key string
+	// Value is the value after Set. It is masked for secrets.
+	Value string
+	// Previous is the value before Set. It is masked for secrets.
+	Previous string
+	// Existed is true when the key was present before Set.
+	Existed bool
+	// Removed is true when the key is not present after Set.
+	Removed bool
+	// Changed is true when Set changed the key.
+	Changed bool
+	// Secret is true when the key matched a secret pattern.
+	Secret bool
+	// Source is the applier that won.
+	Source Source
+}
+
+func (e Entry) String() string {
+	previous := "<unset>"
+	if e.Existed {
+		previous = fmt.Sprintf("%q", e.Previous)
+	}
+
+	if e.Removed {
+		return fmt.Sprintf("%s removed by %s (previous: %s)", e.Key, e.Source, previous)
+	}
+
+	return fmt.Sprintf("%s=%q from %s (previous: %s)", e.Key, e.Value, e.Source, previous)
+}
+
+// Report lists the keys touched by Set sorted by key.
+type Report struct {
+	Entries []Entry
+	// DryRun is true when the environment was not changed.
+	DryRun bool
+}
+
+// Lookup returns the Entry for the key.
+func (r Report) Lookup(key string) (Entry, bool) {
+	for _, entry := range r.Entries {
+		if entry.Key == key {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+func (r Report) String() string {
+	var sb strings.Builder
+	for _, entry := range r.Entries {
+		sb.WriteString(entry.String())
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func newReport(cfg *Config, previous map[string]string, e *environment) Report {
+	var report = Report{DryRun: cfg.dryRun}
+	for key, source := range e.sources {
+		var (
+			prev, existed = previous[key]
+			value, exists = e.values[key]
+		)
+		entry := Entry{
+			Key:      key,
+			Value:    value,
+			Previous: prev,
+			Existed:  existed,
+			Removed:  !exists,
+			Changed:  existed != exists || prev != value,
+			Secret:   source.encrypted || isSecret(cfg.secrets, key),
+			Source:   source,
+		}
+
+		if entry.Secret {
+			if exists {
+				entry.Value = mask
+			}
+			if existed {
+				entry.Previous = mask
+			}
+		}
+
+		report.Entries = append(report.Entries, entry)
+	}
+
+	slices.SortFunc(report.Entries, func(a, b Entry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return report
+}
+
+func isSecret(patterns []string, key string) bool {
+	key = strings.ToUpper(key)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToUpper(pattern), key); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,187 @@
+package env_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"testing"
+
+	"github.com/kyuff/anchor/env"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestSetReport(t *testing.T) {
+	var (
+		newKey = func() string {
+			return fmt.Sprintf("KEY_%d", rand.IntN(100000))
+		}
+		newValue = func() string {
+			return fmt.Sprintf("VALUE_%d", rand.IntN(100000))
+		}
+	)
+
+	t.Run("report override key value", func(t *testing.T) {
+		// arrange
+		var (
+			key      = newKey()
+			previous = newValue()
+			value    = newValue()
+		)
+
+		t.Setenv(key, previous)
+
+		// act
+		report, err := env.SetReport(
+			env.OverrideKeyValue(key, value),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		entry, ok := report.Lookup(key)
+		assert.Truef(t, ok, "missing entry")
+		assert.Equal(t, value, entry.Value)
+		assert.Equal(t, previous, entry.Previous)
+		assert.Truef(t, entry.Existed, "existed")
+		assert.Equal(t, env.Source{Applier: "OverrideKeyValue"}, entry.Source)
+	})
+
+	t.Run("report file that won", func(t *testing.T) {
+		// arrange
+		var (
+			key = "TEST_KEY_412"
+		)
+
+		t.Setenv(key, "")
+		assert.NoError(t, os.Unsetenv(key))
+
+		// act
+		report, err := env.SetReport(
+			env.DefaultKeyValue(key, newValue()),
+			env.OverrideFile("testdata/data.env"),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		entry, ok := report.Lookup(key)
+		assert.Truef(t, ok, "missing entry")
+		assert.Equal(t, "1251a", entry.Value)
+		assert.Falsef(t, entry.Existed, "existed")
+		assert.Equal(t, env.Source{Applier: "OverrideFile", File: "testdata/data.env"}, entry.Source)
+	})
+
+	t.Run("report os when default is skipped", func(t *testing.T) {
+		// arrange
+		var (
+			key   = newKey()
+			value = newValue()
+		)
+
+		t.Setenv(key, value)
+
+		// act
+		report, err := env.SetReport(
+			env.DefaultKeyValue(key, newValue()),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		entry, ok := report.Lookup(key)
+		assert.Truef(t, ok, "missing entry")
+		assert.Equal(t, value, entry.Value)
+		assert.Equal(t, env.Source{Applier: "OS"}, entry.Source)
+	})
+
+	t.Run("mask secrets", func(t *testing.T) {
+		// arrange
+		var (
+			key   = "DB_PASSWORD_" + newKey()
+			value = newValue()
+		)
+
+		// act
+		report, err := env.SetReport(
+			env.OverrideKeyValue(key, value),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		entry, ok := report.Lookup(key)
+		assert.Truef(t, ok, "missing entry")
+		assert.Truef(t, entry.Secret, "secret")
+		assert.Equal(t, "******", entry.Value)
+		assert.Equal(t, value, os.Getenv(key))
+	})
+
+	t.Run("mask custom secret patterns", func(t *testing.T) {
+		// arrange
+		var (
+			key = newKey()
+		)
+
+		// act
+		report, err := env.SetReport(
+			env.OverrideKeyValue(key, newValue()),
+			env.WithSecretPatterns("key_*"),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		entry, _ := report.Lookup(key)
+		assert.Truef(t, entry.Secret, "secret")
+	})
+
+	t.Run("dry run does not set", func(t *testing.T) {
+		// arrange
+		var (
+			key    = newKey()
+			called = 0
+		)
+
+		// act
+		report, err := env.SetReport(
+			env.OverrideKeyValue(key, newValue()),
+			env.WithEnvironment(func(key, value string) error {
+				called++
+				return nil
+			}),
+			env.DryRun(),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Truef(t, report.DryRun, "dry run")
+		assert.Equal(t, 1, len(report.Entries))
+		assert.Equal(t, 0, called)
+	})
+
+	t.Run("report to callbacks", func(t *testing.T) {
+		// arrange
+		var (
+			key    = newKey()
+			got    env.Report
+			logged []string
+		)
+
+		// act
+		err := env.Set(
+			env.OverrideKeyValue(key, newValue()),
+			env.WithReport(func(report env.Report) {
+				got = report
+			}),
+			env.WithReportLog(func(format string, args ...any) {
+				logged = append(logged, fmt.Sprintf(format, args...))
+			}),
+			env.DryRun(),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(got.Entries))
+		assert.Equal(t, 1, len(logged))
+	})
+}
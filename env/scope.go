@@ -0,0 +1,108 @@
+package env
+
+import (
+	"context"
+	"maps"
+	"os"
+	"sync"
+)
+
+// Environment is where configuration values are looked up.
+type Environment interface {
+	LookupEnv(key string) (string, bool)
+}
+
+// Scope is an Environment that is not shared with the process.
+//
+// It allows tests to run in parallel with their own configuration,
+// when the Scope is passed in a context.Context with NewContext
+// and the values are read with Lookup or Getenv.
+type Scope struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewScope creates a Scope that starts out as a copy of the process environment.
+func NewScope() *Scope {
+	return &Scope{values: readOS()}
+}
+
+// LookupEnv returns the value of the key and whether it is present in the Scope.
+func (s *Scope) LookupEnv(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Setenv sets the value of the key in the Scope.
+func (s *Scope) Setenv(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	return nil
+}
+
+// Unsetenv removes the key from the Scope.
+func (s *Scope) Unsetenv(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	return nil
+}
+
+func (s *Scope) environ() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return maps.Clone(s.values)
+}
+
+type processEnvironment struct{}
+
+func (processEnvironment) LookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the Environment.
+func NewContext(ctx context.Context, environment Environment) context.Context {
+	return context.WithValue(ctx, contextKey{}, environment)
+}
+
+// FromContext returns the Environment carried by ctx.
+// It is the process environment if ctx has none.
+func FromContext(ctx context.Context) Environment {
+	if environment, ok := ctx.Value(contextKey{}).(Environment); ok {
+		return environment
+	}
+
+	return processEnvironment{}
+}
+
+// Lookup the key in the Environment carried by ctx.
+func Lookup(ctx context.Context, key string) (string, bool) {
+	return FromContext(ctx).LookupEnv(key)
+}
+
+// Getenv returns the value of the key in the Environment carried by ctx.
+// It is empty if the key is not present.
+func Getenv(ctx context.Context, key string) string {
+	value, _ := Lookup(ctx, key)
+	return value
+}
+
+// Scoped creates a new Scope, Set the options on it and returns it in a copy of ctx.
+func Scoped(ctx context.Context, options ...Option) (context.Context, error) {
+	scope := NewScope()
+	err := Set(append(options, WithScope(scope))...)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewContext(ctx, scope), nil
+}
@@ -0,0 +1,79 @@
+package env_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kyuff/anchor/env"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestScope(t *testing.T) {
+	t.Run("set values in scope", func(t *testing.T) {
+		t.Parallel()
+
+		// arrange
+		var (
+			key   = "SCOPE_TEST_KEY"
+			scope = env.NewScope()
+			ctx   = env.NewContext(t.Context(), scope)
+		)
+
+		// act
+		err := env.Set(
+			env.OverrideKeyValue(key, "scoped"),
+			env.WithScope(scope),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "scoped", env.Getenv(ctx, key))
+		_, ok := os.LookupEnv(key)
+		assert.Falsef(t, ok, "process environment changed")
+	})
+
+	t.Run("unset values in scope", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		ctx, err := env.Scoped(t.Context(), env.Unset("PATH"))
+
+		// assert
+		assert.NoError(t, err)
+		_, ok := env.Lookup(ctx, "PATH")
+		assert.Falsef(t, ok, "PATH still in scope")
+		assert.Truef(t, os.Getenv("PATH") != "", "process environment changed")
+	})
+
+	t.Run("read from file into scope", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		ctx, err := env.Scoped(t.Context(), env.OverrideFile("testdata/data.env"))
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "1251a", env.Getenv(ctx, "TEST_KEY_412"))
+	})
+
+	t.Run("fail on missing file", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		_, err := env.Scoped(t.Context(), env.OverrideFile("testdata/not_there.env"))
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("fall back to process environment", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		value, ok := env.Lookup(t.Context(), "PATH")
+
+		// assert
+		assert.Truef(t, ok, "missing PATH")
+		assert.Equal(t, os.Getenv("PATH"), value)
+	})
+}
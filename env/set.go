@@ -1,26 +1,71 @@
 package env
 
+import (
+	"maps"
+	"slices"
+)
+
 type TestingT interface {
 	Setenv(key, val string)
 }
 
 func Set(options ...Option) error {
+	_, err := SetReport(options...)
+	return err
+}
+
+// SetReport works like Set, but returns a Report of where each touched key got its value from.
+func SetReport(options ...Option) (Report, error) {
 	cfg := applyOptions(defaultOptions(), options...)
 
-	var kv = readOS()
+	var (
+		previous = cfg.reader()
+		e        = newEnvironment(previous)
+	)
 	for _, apply := range cfg.appliers {
-		err := apply(kv)
+		err := apply(e)
 		if err != nil {
-			return err
+			return Report{}, err
+		}
+	}
+
+	report := newReport(cfg, previous, e)
+	if !cfg.dryRun {
+		if err := write(cfg, previous, e.values); err != nil {
+			return Report{}, err
 		}
 	}
 
-	for key, value := range kv {
+	for _, fn := range cfg.reporters {
+		fn(report)
+	}
+
+	return report, nil
+}
+
+// write changes the keys that differ between previous and values.
+func write(cfg *Config, previous, values map[string]string) error {
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value := values[key]
+		if prev, ok := previous[key]; ok && prev == value {
+			continue
+		}
+
 		if err := cfg.setter(key, value); err != nil {
 			return err
 		}
 	}
 
+	for _, key := range slices.Sorted(maps.Keys(previous)) {
+		if _, ok := values[key]; ok {
+			continue
+		}
+
+		if err := cfg.unsetter(key); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -304,4 +304,82 @@ func TestSet(t *testing.T) {
 		// assert
 		assert.Error(t, err)
 	})
+
+	t.Run("unset key", func(t *testing.T) {
+		// arrange
+		var (
+			key = newKey()
+		)
+
+		t.Setenv(key, newValue())
+
+		// act
+		err := env.Set(
+			env.Unset(key),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		_, ok := os.LookupEnv(key)
+		assert.Falsef(t, ok, "key %s still set", key)
+	})
+
+	t.Run("only set changed keys", func(t *testing.T) {
+		// arrange
+		var (
+			key     = newKey()
+			value   = newValue()
+			changed []string
+		)
+
+		t.Setenv(key, value)
+
+		// act
+		err := env.Set(
+			env.OverrideKeyValue(key, value),
+			env.OverrideKeyValue("NEW_"+key, value),
+			env.WithEnvironment(func(key, value string) error {
+				changed = append(changed, key)
+				return nil
+			}),
+		)
+
+		// assert
+		assert.NoError(t, err)
+		assert.EqualSlice(t, []string{"NEW_" + key}, changed)
+	})
+
+	t.Run("allow parallel tests when nothing changes", func(t *testing.T) {
+		t.Parallel()
+
+		// act
+		err := env.Set(
+			env.DefaultKeyValue("PATH", newValue()),
+			env.WithT(t),
+		)
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("fail on unsetting environment", func(t *testing.T) {
+		// arrange
+		var (
+			key = newKey()
+		)
+
+		t.Setenv(key, newValue())
+
+		// act
+		err := env.Set(
+			env.Unset(key),
+			env.WithEnvironmentUnset(func(key string) error {
+				return errors.New("test")
+			}),
+		)
+
+		// assert
+		assert.Error(t, err)
+	})
 }
@@ -0,0 +1,24 @@
+package env
+
+import "os"
+
+// State is a copy of the process environment taken by Snapshot.
+type State struct {
+	values map[string]string
+}
+
+// Snapshot copies the process environment, so it can be restored later.
+//
+// It gives callers outside of tests the same isolation as WithT.
+func Snapshot() State {
+	return State{values: readOS()}
+}
+
+// Restore changes the process environment back to the State.
+// Only keys that changed since the Snapshot are written.
+func (s State) Restore() error {
+	return write(&Config{
+		setter:   os.Setenv,
+		unsetter: os.Unsetenv,
+	}, readOS(), s.values)
+}
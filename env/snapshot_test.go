@@ -0,0 +1,43 @@
+package env_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/kyuff/anchor/env"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Run("restore changed, added and removed keys", func(t *testing.T) {
+		// arrange
+		var (
+			changed = "SNAPSHOT_CHANGED_KEY"
+			added   = "SNAPSHOT_ADDED_KEY"
+			removed = "SNAPSHOT_REMOVED_KEY"
+		)
+
+		t.Setenv(changed, "before")
+		t.Setenv(removed, "before")
+		t.Setenv(added, "")
+		assert.NoError(t, os.Unsetenv(added))
+
+		sut := env.Snapshot()
+
+		assert.NoError(t, env.Set(
+			env.OverrideKeyValue(changed, "after"),
+			env.OverrideKeyValue(added, "after"),
+			env.Unset(removed),
+		))
+
+		// act
+		err := sut.Restore()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, "before", os.Getenv(changed))
+		assert.Equal(t, "before", os.Getenv(removed))
+		_, ok := os.LookupEnv(added)
+		assert.Falsef(t, ok, "key %s still set", added)
+	})
+}
@@ -0,0 +1,267 @@
+package anchor
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// historySize is the number of recent events kept for AdminHandler.
+const historySize = 100
+
+// EventKind tells what happened in an Event.
+type EventKind string
+
+const (
+	// EventRun is sent when the Anchor begins to Run.
+	EventRun EventKind = "run"
+	// EventSetup is sent when a Component is Setup. Err is set when it failed.
+	EventSetup EventKind = "setup"
+	// EventStarted is sent when Start is called on a Component.
+	EventStarted EventKind = "started"
+	// EventProbeFailed is sent when a Probe of a Component fails. Attempt is the number of the Probe.
+	EventProbeFailed EventKind = "probe-failed"
+	// EventComponentReady is sent when a Component succeeds a Probe.
+	EventComponentReady EventKind = "component-ready"
+	// EventReady is sent when the Anchor is ready.
+	EventReady EventKind = "ready"
+	// EventStopped is sent when Start of a Component returns. Err is what it returned.
+	EventStopped EventKind = "stopped"
+	// EventShutdown is sent when the Anchor begins to close. Cause tells why.
+	EventShutdown EventKind = "shutdown"
+	// EventClosed is sent when a Component is Closed. Err is set when it failed.
+	EventClosed EventKind = "closed"
+	// EventDone is sent when Run is about to return Code. It is the last Event.
+	EventDone EventKind = "done"
+)
+
+// Event in the lifecycle of an Anchor.
+type Event struct {
+	Kind EventKind
+	At   time.Time
+	// Component is empty for events of the Anchor.
+	Component string
+	Attempt   int
+	// Cause of a shutdown: context, shutdown, completed, setup_failed or error.
+	Cause string
+	// Code is the exit code of Run.
+	Code int
+	Err  error
+	// Dropped is the number of events that were dropped before this one,
+	// as the buffer was full.
+	Dropped int
+}
+
+// MarshalJSON encodes the error by its message.
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind      EventKind `json:"kind"`
+		At        time.Time `json:"at"`
+		Component string    `json:"component,omitempty"`
+		Attempt   int       `json:"attempt,omitempty"`
+		Cause     string    `json:"cause,omitempty"`
+		Code      int       `json:"code,omitempty"`
+		Err       string    `json:"error,omitempty"`
+		Dropped   int       `json:"dropped,omitempty"`
+	}{e.Kind, e.At, e.Component, e.Attempt, e.Cause, e.Code, errorString(e.Err), e.Dropped})
+}
+
+// EventOption for a subscription to Events.
+type EventOption func(cfg *eventConfig)
+
+type eventConfig struct {
+	buffer     int
+	dropOldest bool
+}
+
+// WithEventBuffer sets the number of events that are buffered for a slow receiver.
+//
+// Default: 64
+func WithEventBuffer(size int) EventOption {
+	return func(cfg *eventConfig) {
+		cfg.buffer = max(1, size)
+	}
+}
+
+// WithEventDropOldest drops the oldest buffered event to make room for a new one,
+// when the buffer is full.
+//
+// Default: the new event is dropped
+func WithEventDropOldest() EventOption {
+	return func(cfg *eventConfig) {
+		cfg.dropOldest = true
+	}
+}
+
+// Events subscribes to the lifecycle events of the Anchor. They are delivered in order.
+//
+// The Anchor never waits for a receiver. When the buffer is full, events are dropped,
+// and the Dropped count is set on the next event that is delivered.
+//
+// The channel is closed after EventDone, or when ctx is done.
+//
+// There is no event for a restart. An Anchor runs once and never starts a Component
+// again, so a restart is a new Anchor in the process. Metrics shared by the Anchors
+// counts those restarts.
+func (a *Anchor) Events(ctx context.Context, opts ...EventOption) <-chan Event {
+	cfg := &eventConfig{buffer: 64}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return a.events.subscribe(ctx, cfg)
+}
+
+// broker sends Events to the subscribers and keeps the recent ones.
+type broker struct {
+	mu       sync.Mutex
+	subs     map[*subscriber]struct{}
+	attempts map[int]int
+	history  []Event
+	closed   bool
+}
+
+type subscriber struct {
+	ch      chan Event
+	cfg     *eventConfig
+	dropped int
+}
+
+func newBroker() *broker {
+	return &broker{
+		subs:     make(map[*subscriber]struct{}),
+		attempts: make(map[int]int),
+	}
+}
+
+func (b *broker) subscribe(ctx context.Context, cfg *eventConfig) <-chan Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &subscriber{ch: make(chan Event, cfg.buffer), cfg: cfg}
+	if b.closed {
+		close(sub.ch)
+		return sub.ch
+	}
+
+	b.subs[sub] = struct{}{}
+	context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subs[sub]; ok {
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	})
+
+	return sub.ch
+}
+
+// recent returns the latest events, oldest first.
+func (b *broker) recent() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]Event(nil), b.history...)
+}
+
+func (b *broker) publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.history) == historySize {
+		b.history = append(b.history[:0], b.history[1:]...)
+	}
+	b.history = append(b.history, e)
+
+	for sub := range b.subs {
+		sub.send(e)
+	}
+
+	if e.Kind == EventDone {
+		b.closed = true
+		for sub := range b.subs {
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func (s *subscriber) send(e Event) {
+	e.Dropped = s.dropped
+	select {
+	case s.ch <- e:
+		s.dropped = 0
+		return
+	default:
+	}
+
+	if !s.cfg.dropOldest {
+		s.dropped++
+		return
+	}
+
+	select {
+	case old := <-s.ch:
+		// the count on the dropped event carries over to the new one
+		s.dropped += old.Dropped + 1
+	default:
+	}
+
+	e.Dropped = s.dropped
+	select {
+	case s.ch <- e:
+		s.dropped = 0
+	default:
+		s.dropped++
+	}
+}
+
+func (b *broker) run(_ []string, at time.Time) {
+	b.publish(Event{Kind: EventRun, At: at})
+}
+
+func (b *broker) begin(index int, component, phase string, at time.Time) {
+	switch phase {
+	case "Start":
+		b.publish(Event{Kind: EventStarted, At: at, Component: component})
+	case "Probe":
+		b.mu.Lock()
+		b.attempts[index]++
+		b.mu.Unlock()
+	}
+}
+
+func (b *broker) end(index int, component, phase string, at time.Time, err error) {
+	switch phase {
+	case "Setup":
+		b.publish(Event{Kind: EventSetup, At: at, Component: component, Err: err})
+	case "Start":
+		b.publish(Event{Kind: EventStopped, At: at, Component: component, Err: err})
+	case "Probe":
+		b.mu.Lock()
+		attempt := b.attempts[index]
+		b.mu.Unlock()
+		if err != nil {
+			b.publish(Event{Kind: EventProbeFailed, At: at, Component: component, Attempt: attempt, Err: err})
+		} else {
+			b.publish(Event{Kind: EventComponentReady, At: at, Component: component, Attempt: attempt})
+		}
+	case "Close":
+		b.publish(Event{Kind: EventClosed, At: at, Component: component, Err: err})
+	}
+}
+
+func (b *broker) ready(at time.Time) {
+	b.publish(Event{Kind: EventReady, At: at})
+}
+
+func (b *broker) shutdown(cause string, at time.Time) {
+	b.publish(Event{Kind: EventShutdown, At: at, Cause: cause})
+}
+
+func (b *broker) done(code int, at time.Time) {
+	b.publish(Event{Kind: EventDone, At: at, Code: code})
+}
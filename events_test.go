@@ -0,0 +1,240 @@
+package anchor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestEvents(t *testing.T) {
+	var (
+		newAnchor = func(components ...anchor.Component) *anchor.Anchor {
+			wire := anchor.ManualWire()
+			return anchor.New(wire,
+				anchor.WithFixedReadyCheckBackoff(time.Millisecond),
+				anchor.WithReadyCallback(func(ctx context.Context) error {
+					wire.Stop(nil)
+					return nil
+				}),
+			).Add(components...)
+		}
+		collect = func(events <-chan anchor.Event) []anchor.Event {
+			var got []anchor.Event
+			for e := range events {
+				got = append(got, e)
+			}
+			return got
+		}
+		kinds = func(events []anchor.Event, skip ...anchor.EventKind) []anchor.EventKind {
+			var got []anchor.EventKind
+		next:
+			for _, e := range events {
+				for _, s := range skip {
+					if e.Kind == s {
+						continue next
+					}
+				}
+				got = append(got, e.Kind)
+			}
+			return got
+		}
+	)
+
+	t.Run("deliver the lifecycle in order", func(t *testing.T) {
+		// arrange
+		var (
+			sut    = newAnchor(&tracedComponent{name: "db"}, &tracedComponent{name: "http"})
+			events = sut.Events(t.Context(), anchor.WithEventBuffer(1000))
+		)
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		got := collect(events)
+		assert.EqualSlice(t, []anchor.EventKind{
+			anchor.EventRun,
+			anchor.EventSetup,
+			anchor.EventSetup,
+			anchor.EventStarted,
+			anchor.EventStarted,
+			anchor.EventComponentReady,
+			anchor.EventComponentReady,
+			anchor.EventReady,
+			anchor.EventShutdown,
+			anchor.EventClosed,
+			anchor.EventClosed,
+			anchor.EventDone,
+		}, kinds(got, anchor.EventProbeFailed, anchor.EventStopped))
+		assert.Equal(t, "db", got[1].Component)
+		assert.Equal(t, "http", got[2].Component)
+		for _, e := range got {
+			switch e.Kind {
+			case anchor.EventShutdown:
+				assert.Equal(t, "context", e.Cause)
+			case anchor.EventClosed:
+				assert.NoError(t, e.Err)
+			case anchor.EventDone:
+				assert.Equal(t, anchor.OK, e.Code)
+			}
+			assert.Equal(t, 0, e.Dropped)
+			assert.Truef(t, !e.At.IsZero(), "event at: %+v", e)
+		}
+	})
+
+	t.Run("stop each component before done", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newAnchor(anchor.Func("slow", func(ctx context.Context) error {
+				<-ctx.Done()
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			}))
+			events = sut.Events(t.Context(), anchor.WithEventBuffer(1000))
+		)
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		got := kinds(collect(events), anchor.EventProbeFailed)
+		assert.EqualSlice(t, []anchor.EventKind{
+			anchor.EventStopped,
+			anchor.EventDone,
+		}, got[len(got)-2:])
+	})
+
+	t.Run("count probe attempts", func(t *testing.T) {
+		// arrange
+		var (
+			sut    = newAnchor(&tracedComponent{name: "db", probes: 3})
+			events = sut.Events(t.Context(), anchor.WithEventBuffer(1000))
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		attempt := 0
+		for _, e := range collect(events) {
+			switch e.Kind {
+			case anchor.EventProbeFailed:
+				attempt++
+				assert.Equal(t, attempt, e.Attempt)
+				assert.Error(t, e.Err)
+			case anchor.EventComponentReady:
+				assert.Equal(t, attempt+1, e.Attempt)
+			}
+		}
+		assert.Truef(t, attempt >= 2, "failed attempts: %d", attempt)
+	})
+
+	t.Run("report failed setup", func(t *testing.T) {
+		// arrange
+		var (
+			setupErr = errors.New("boom")
+			sut      = newAnchor(&tracedComponent{name: "db", setupErr: setupErr})
+			events   = sut.Events(t.Context())
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		got := collect(events)
+		if assert.Equal(t, 5, len(got)) {
+			assert.Equal(t, anchor.EventSetup, got[1].Kind)
+			assert.Truef(t, errors.Is(got[1].Err, setupErr), "setup error: %v", got[1].Err)
+			assert.Equal(t, anchor.EventShutdown, got[2].Kind)
+			assert.Equal(t, "setup_failed", got[2].Cause)
+			assert.Equal(t, anchor.SetupFailed, got[4].Code)
+		}
+	})
+
+	t.Run("drop new events when full", func(t *testing.T) {
+		// arrange
+		var (
+			sut    = newAnchor(&tracedComponent{name: "db"})
+			events = sut.Events(t.Context(), anchor.WithEventBuffer(2))
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		assert.EqualSlice(t, []anchor.EventKind{anchor.EventRun, anchor.EventSetup}, kinds(collect(events)))
+	})
+
+	t.Run("drop old events when full", func(t *testing.T) {
+		// arrange
+		var (
+			sut    = newAnchor(&tracedComponent{name: "db"})
+			events = sut.Events(t.Context(), anchor.WithEventBuffer(1), anchor.WithEventDropOldest())
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		got := collect(events)
+		if assert.Equal(t, 1, len(got)) {
+			assert.Equal(t, anchor.EventDone, got[0].Kind)
+			assert.Truef(t, got[0].Dropped > 5, "dropped: %d", got[0].Dropped)
+		}
+	})
+
+	t.Run("close when the context is done", func(t *testing.T) {
+		// arrange
+		var (
+			ctx, cancel = context.WithCancel(t.Context())
+			sut         = newAnchor(&tracedComponent{name: "db"})
+			events      = sut.Events(ctx)
+		)
+
+		// act
+		cancel()
+
+		// assert
+		select {
+		case _, ok := <-events:
+			assert.Falsef(t, ok, "expected closed channel")
+		case <-time.After(time.Second):
+			t.Fatal("channel not closed")
+		}
+	})
+
+	t.Run("close when subscribed after run", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newAnchor(&tracedComponent{name: "db"})
+		)
+
+		sut.Run()
+
+		// act
+		got := collect(sut.Events(t.Context()))
+
+		// assert
+		assert.Equal(t, 0, len(got))
+	})
+
+	t.Run("close when there are no components", func(t *testing.T) {
+		// arrange
+		var (
+			sut    = anchor.New(anchor.ManualWire())
+			events = sut.Events(t.Context())
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		assert.EqualSlice(t, []anchor.EventKind{anchor.EventDone}, kinds(collect(events)))
+	})
+}
@@ -0,0 +1,152 @@
+package anchor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kyuff/anchor/internal/clock"
+)
+
+// EveryOption for a Component made by Every.
+type EveryOption func(cfg *everyConfig)
+
+type everyConfig struct {
+	jobConfig
+	initialDelay time.Duration
+}
+
+// WithEveryInitialDelay waits d before the first run.
+//
+// Default: the interval
+func WithEveryInitialDelay(d time.Duration) EveryOption {
+	return func(cfg *everyConfig) {
+		cfg.initialDelay = d
+	}
+}
+
+// WithEveryJitter adds a random wait up to d before each run, to spread the runs of
+// several instances of the application.
+//
+// Default: No jitter
+func WithEveryJitter(d time.Duration) EveryOption {
+	return func(cfg *everyConfig) {
+		cfg.jitter = d
+	}
+}
+
+// WithEveryOverlap sets what happens when a run is due while the previous run is not done.
+//
+// Default: OverlapSkip
+func WithEveryOverlap(overlap Overlap) EveryOption {
+	return func(cfg *everyConfig) {
+		cfg.overlap = overlap
+	}
+}
+
+// WithEveryTimeout cancels the context of a run after d.
+//
+// Default: No timeout
+func WithEveryTimeout(d time.Duration) EveryOption {
+	return func(cfg *everyConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithEveryOnError is called with the error of each run that fails, e.g. to log it.
+//
+// Default: the error is only counted
+func WithEveryOnError(fn func(ctx context.Context, err error)) EveryOption {
+	return func(cfg *everyConfig) {
+		cfg.onError = fn
+	}
+}
+
+// WithEveryFailAfter fails the Anchor when n runs in a row return an error.
+//
+// Default: the Anchor does not fail
+func WithEveryFailAfter(n int) EveryOption {
+	return func(cfg *everyConfig) {
+		cfg.failAfter = n
+	}
+}
+
+// Every creates a Component that calls fn each interval while it is Started.
+//
+// The runs are timed by the Clock of the Anchor. When the Anchor closes, no new runs
+// are made and Close waits for those in flight. Their context is cancelled if
+// they are not done before the close timeout.
+//
+// An interval that is not positive fails the Setup of the Component.
+func Every(name string, interval time.Duration, fn func(ctx context.Context) error, opts ...EveryOption) *EveryComponent {
+	cfg := &everyConfig{
+		jobConfig:    defaultJobConfig(),
+		initialDelay: interval,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	var err error
+	if interval <= 0 {
+		err = fmt.Errorf("every %q: interval %s is not positive", name, interval)
+	}
+
+	return &EveryComponent{
+		name:         name,
+		initialDelay: cfg.initialDelay,
+		err:          err,
+		job: newJob(fn, &cfg.jobConfig, func(last, now time.Time) time.Time {
+			// skip the runs that were missed, instead of running them in a burst
+			next := last.Add(interval)
+			for !next.After(now) {
+				next = next.Add(interval)
+			}
+			return next
+		}),
+	}
+}
+
+// EveryComponent runs a job periodically. It is made by Every.
+type EveryComponent struct {
+	name         string
+	initialDelay time.Duration
+	err          error
+	job          *job
+}
+
+// Name of the Component.
+func (c *EveryComponent) Name() string {
+	return c.name
+}
+
+// Runs is the number of runs that are done.
+func (c *EveryComponent) Runs() int {
+	runs, _ := c.job.counts()
+	return runs
+}
+
+// Errors is the number of runs that returned an error.
+func (c *EveryComponent) Errors() int {
+	_, errors := c.job.counts()
+	return errors
+}
+
+// Setup fails if the interval is not positive.
+func (c *EveryComponent) Setup(ctx context.Context) error {
+	return c.err
+}
+
+// Start runs the job each interval until ctx is done.
+func (c *EveryComponent) Start(ctx context.Context) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.job.start(ctx, clock.FromContext(ctx).Now().Add(c.initialDelay))
+}
+
+// Close waits for the runs in flight. If ctx is done first, their context is cancelled.
+func (c *EveryComponent) Close(ctx context.Context) error {
+	return c.job.close(ctx)
+}
@@ -0,0 +1,325 @@
+package anchor_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/anchortest"
+	"github.com/kyuff/anchor/internal/assert"
+	"github.com/kyuff/anchor/internal/clock"
+)
+
+func TestEvery(t *testing.T) {
+	var (
+		interval = time.Minute
+		start    = func(t *testing.T, sut *anchor.EveryComponent) (*anchortest.Clock, context.CancelFunc, <-chan error) {
+			t.Helper()
+			var (
+				clk         = anchortest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+				ctx, cancel = context.WithCancel(clock.NewContext(t.Context(), clk))
+				errs        = make(chan error, 1)
+			)
+			go func() { errs <- sut.Start(ctx) }()
+			return clk, cancel, errs
+		}
+		tick = func(clk *anchortest.Clock) {
+			clk.BlockUntil(1)
+			clk.Advance(interval)
+		}
+		waitRuns = func(sut *anchor.EveryComponent, n int) {
+			for sut.Runs() < n {
+				time.Sleep(time.Millisecond)
+			}
+		}
+		calling = func(calls chan<- struct{}, release <-chan struct{}, err error) func(ctx context.Context) error {
+			return func(ctx context.Context) error {
+				calls <- struct{}{}
+				<-release
+				return err
+			}
+		}
+		released = func() chan struct{} {
+			ch := make(chan struct{})
+			close(ch)
+			return ch
+		}
+	)
+
+	t.Run("run each interval", func(t *testing.T) {
+		// arrange
+		var (
+			calls = make(chan struct{}, 10)
+			sut   = anchor.Every("job", interval, calling(calls, released(), nil))
+		)
+		clk, cancel, errs := start(t, sut)
+
+		// act
+		for i := range 3 {
+			tick(clk)
+			<-calls
+			waitRuns(sut, i+1)
+		}
+		cancel()
+
+		// assert
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, 3, sut.Runs())
+		assert.Equal(t, 0, sut.Errors())
+		assert.Equal(t, "job", sut.Name())
+	})
+
+	t.Run("run at once without initial delay", func(t *testing.T) {
+		// arrange
+		var (
+			calls = make(chan struct{}, 10)
+			sut   = anchor.Every("job", interval, calling(calls, released(), nil), anchor.WithEveryInitialDelay(0))
+		)
+		_, cancel, errs := start(t, sut)
+
+		// act
+		<-calls
+		cancel()
+
+		// assert
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, 1, sut.Runs())
+	})
+
+	t.Run("skip when the previous run is not done", func(t *testing.T) {
+		// arrange
+		var (
+			calls   = make(chan struct{}, 10)
+			release = make(chan struct{})
+			sut     = anchor.Every("job", interval, calling(calls, release, nil))
+		)
+		clk, cancel, errs := start(t, sut)
+		tick(clk)
+		<-calls
+
+		// act
+		tick(clk)
+		tick(clk)
+		clk.BlockUntil(1)
+		close(release)
+		cancel()
+
+		// assert
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, 1, sut.Runs())
+	})
+
+	t.Run("queue one run when the previous run is not done", func(t *testing.T) {
+		// arrange
+		var (
+			calls   = make(chan struct{}, 10)
+			release = make(chan struct{})
+			sut     = anchor.Every("job", interval, calling(calls, release, nil), anchor.WithEveryOverlap(anchor.OverlapQueue))
+		)
+		clk, cancel, errs := start(t, sut)
+		tick(clk)
+		<-calls
+
+		// act
+		tick(clk)
+		tick(clk)
+		clk.BlockUntil(1)
+		close(release)
+		cancel()
+
+		// assert
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, 2, sut.Runs())
+	})
+
+	t.Run("run concurrently with the previous run", func(t *testing.T) {
+		// arrange
+		var (
+			calls   = make(chan struct{}, 10)
+			release = make(chan struct{})
+			sut     = anchor.Every("job", interval, calling(calls, release, nil), anchor.WithEveryOverlap(anchor.OverlapConcurrent))
+		)
+		clk, cancel, errs := start(t, sut)
+
+		// act
+		tick(clk)
+		<-calls
+		tick(clk)
+		<-calls
+		close(release)
+		cancel()
+
+		// assert
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, 2, sut.Runs())
+	})
+
+	t.Run("report errors", func(t *testing.T) {
+		// arrange
+		var (
+			runErr   = errors.New("boom")
+			calls    = make(chan struct{}, 10)
+			reported = make(chan error, 10)
+			sut      = anchor.Every("job", interval, calling(calls, released(), runErr),
+				anchor.WithEveryOnError(func(ctx context.Context, err error) {
+					reported <- err
+				}),
+			)
+		)
+		clk, cancel, errs := start(t, sut)
+
+		// act
+		tick(clk)
+		waitRuns(sut, 1)
+		tick(clk)
+		err := errors.Join(<-reported, <-reported)
+		cancel()
+
+		// assert
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Truef(t, errors.Is(err, runErr), "reported: %v", err)
+		assert.Equal(t, 2, sut.Errors())
+	})
+
+	t.Run("fail after errors in a row", func(t *testing.T) {
+		// arrange
+		var (
+			runErr = errors.New("boom")
+			runs   atomic.Int32
+			sut    = anchor.Every("job", interval, func(ctx context.Context) error {
+				// a success in between resets the count
+				if runs.Add(1) == 2 {
+					return nil
+				}
+				return runErr
+			}, anchor.WithEveryFailAfter(2))
+		)
+		clk, cancel, errs := start(t, sut)
+		defer cancel()
+
+		// act
+		for i := range 4 {
+			tick(clk)
+			waitRuns(sut, i+1)
+		}
+
+		// assert
+		err := <-errs
+		assert.Truef(t, errors.Is(err, runErr), "start error: %v", err)
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, 4, sut.Runs())
+		assert.Equal(t, 3, sut.Errors())
+	})
+
+	t.Run("time out a run", func(t *testing.T) {
+		// arrange
+		var (
+			reported = make(chan error, 1)
+			sut      = anchor.Every("job", interval, func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+				anchor.WithEveryTimeout(time.Second),
+				anchor.WithEveryOnError(func(ctx context.Context, err error) {
+					reported <- err
+				}),
+			)
+		)
+		clk, cancel, errs := start(t, sut)
+		tick(clk)
+
+		// act
+		clk.BlockUntil(2)
+		clk.Advance(time.Second)
+
+		// assert
+		assert.Truef(t, errors.Is(<-reported, context.DeadlineExceeded), "expected a timeout")
+		cancel()
+		assert.NoError(t, <-errs)
+		assert.NoError(t, sut.Close(t.Context()))
+	})
+
+	t.Run("cancel the run when close times out", func(t *testing.T) {
+		// arrange
+		var (
+			calls    = make(chan struct{}, 10)
+			canceled = make(chan struct{})
+			sut      = anchor.Every("job", interval, func(ctx context.Context) error {
+				calls <- struct{}{}
+				<-ctx.Done()
+				close(canceled)
+				return ctx.Err()
+			})
+		)
+		clk, cancel, errs := start(t, sut)
+		tick(clk)
+		<-calls
+		cancel()
+		assert.NoError(t, <-errs)
+
+		ctx, cancelClose := context.WithCancel(t.Context())
+		cancelClose()
+
+		// act
+		err := sut.Close(ctx)
+
+		// assert
+		assert.Truef(t, errors.Is(err, context.Canceled), "close error: %v", err)
+		<-canceled
+	})
+
+	t.Run("close before start", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.Every("job", interval, func(ctx context.Context) error { return nil })
+		)
+
+		// act
+		err := sut.Close(t.Context())
+
+		// assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("fail the anchor", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.Every("job", time.Millisecond, func(ctx context.Context) error {
+				return errors.New("boom")
+			}, anchor.WithEveryFailAfter(3))
+		)
+
+		// act
+		code := anchor.New(anchor.ManualWire()).Add(sut).Run()
+
+		// assert
+		assert.Equal(t, anchor.Internal, code)
+		assert.Equal(t, 3, sut.Errors())
+	})
+
+	t.Run("fail on an interval that is not positive", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.Every("job", 0, func(ctx context.Context) error { return nil }, anchor.WithEveryInitialDelay(10*time.Millisecond))
+		)
+
+		// act
+		setupErr := sut.Setup(t.Context())
+		startErr := sut.Start(t.Context())
+		code := anchor.New(anchor.ManualWire()).Add(sut).Run()
+
+		// assert
+		assert.Error(t, setupErr)
+		assert.Error(t, startErr)
+		assert.Equal(t, anchor.SetupFailed, code)
+	})
+}
@@ -0,0 +1,206 @@
+package anchor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// HTTPOption for a Component made by HTTPServer.
+type HTTPOption func(cfg *httpConfig)
+
+type httpConfig struct {
+	tls      bool
+	certFile string
+	keyFile  string
+	h2c      bool
+}
+
+// WithHTTPTLS serves HTTPS with the certificate and key in the files.
+// The files can be empty when the TLSConfig of the http.Server has the certificates.
+//
+// Default: HTTP
+func WithHTTPTLS(certFile, keyFile string) HTTPOption {
+	return func(cfg *httpConfig) {
+		cfg.tls = true
+		cfg.certFile = certFile
+		cfg.keyFile = keyFile
+	}
+}
+
+// WithHTTPH2C serves HTTP/2 without TLS, also known as h2c, next to HTTP/1.
+// It is added to the Protocols of the http.Server, so HTTP/2 over TLS is kept.
+//
+// Default: HTTP/1 only, when not using TLS
+func WithHTTPH2C() HTTPOption {
+	return func(cfg *httpConfig) {
+		cfg.h2c = true
+	}
+}
+
+// HTTPServer creates a Component that runs the http.Server.
+//
+// The listener is bound in Setup with Listen by the name, so an address that is in use fails the Anchor
+// with SetupFailed, and a listener passed by systemd socket activation is used.
+// The server is ready when its address accepts connections. It is shut down gracefully
+// within the close timeout of the Anchor, and closed if that does not complete in time.
+func HTTPServer(name string, server *http.Server, opts ...HTTPOption) *HTTPComponent {
+	cfg := &httpConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &HTTPComponent{
+		name:   name,
+		server: server,
+		cfg:    cfg,
+	}
+}
+
+// HTTPComponent is an http.Server managed by an Anchor. It is made by HTTPServer.
+type HTTPComponent struct {
+	name   string
+	server *http.Server
+	cfg    *httpConfig
+
+	mu       sync.Mutex
+	listener net.Listener
+}
+
+// Name of the Component.
+func (c *HTTPComponent) Name() string {
+	return c.name
+}
+
+// Addr is the address the server listens on. It is nil before Setup.
+//
+// Use it to find the port when the server is given the address ":0".
+func (c *HTTPComponent) Addr() net.Addr {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.listener == nil {
+		return nil
+	}
+
+	return c.listener.Addr()
+}
+
+// Setup binds the listener of the server, or takes it from socket activation.
+func (c *HTTPComponent) Setup(ctx context.Context) error {
+	addr := c.server.Addr
+	if addr == "" {
+		addr = ":http"
+		if c.cfg.tls {
+			addr = ":https"
+		}
+	}
+
+	if c.cfg.h2c {
+		// add to the protocols of the server, or to the defaults of http.Server when none are set
+		protocols := new(http.Protocols)
+		if c.server.Protocols != nil {
+			*protocols = *c.server.Protocols
+		} else {
+			protocols.SetHTTP1(true)
+			protocols.SetHTTP2(true)
+		}
+		protocols.SetUnencryptedHTTP2(true)
+		c.server.Protocols = protocols
+	}
+
+	listener, err := Listen(ctx, c.name, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.listener = listener
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Start serves requests until the server is closed.
+func (c *HTTPComponent) Start(ctx context.Context) error {
+	listener, err := c.bound()
+	if err != nil {
+		return err
+	}
+
+	if c.cfg.tls {
+		err = c.server.ServeTLS(listener, c.cfg.certFile, c.cfg.keyFile)
+	} else {
+		err = c.server.Serve(listener)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Probe dials the address of the server.
+func (c *HTTPComponent) Probe(ctx context.Context) error {
+	listener, err := c.bound()
+	if err != nil {
+		return err
+	}
+
+	var dialer interface {
+		DialContext(ctx context.Context, network, address string) (net.Conn, error)
+	} = &net.Dialer{}
+	if c.cfg.tls {
+		// complete the handshake, so the server does not log it as failed.
+		// The certificate is not verified, as the server dials itself.
+		dialer = &tls.Dialer{Config: &tls.Config{InsecureSkipVerify: true}}
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", listener.Addr().String())
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+// Close shuts down the server gracefully, and closes it when ctx is done first.
+func (c *HTTPComponent) Close(ctx context.Context) error {
+	c.mu.Lock()
+	listener := c.listener
+	c.mu.Unlock()
+
+	if listener == nil {
+		// Setup failed, so there is nothing to close
+		return nil
+	}
+
+	err := c.server.Shutdown(ctx)
+	if err != nil {
+		err = errors.Join(fmt.Errorf("graceful shutdown: %w", err), c.server.Close())
+	}
+
+	// the server only closes the listener if it was served
+	if closeErr := listener.Close(); closeErr != nil && !errors.Is(closeErr, net.ErrClosed) {
+		err = errors.Join(err, closeErr)
+	}
+
+	return err
+}
+
+// bound returns the listener, or an error when Setup has not bound it.
+func (c *HTTPComponent) bound() (net.Listener, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.listener == nil {
+		return nil, errors.New("not set up")
+	}
+
+	return c.listener, nil
+}
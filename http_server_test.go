@@ -0,0 +1,224 @@
+package anchor_test
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestHTTPServer(t *testing.T) {
+	var (
+		hello = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, "hello %s", r.Proto)
+		})
+		runAndGet = func(t *testing.T, sut *anchor.HTTPComponent, client *http.Client, scheme string) (int, string) {
+			t.Helper()
+			var (
+				wire = anchor.ManualWire()
+				body string
+			)
+			code := anchor.New(wire, anchor.WithReadyCallback(func(ctx context.Context) error {
+				defer wire.Stop(nil)
+				res, err := client.Get(fmt.Sprintf("%s://%s/", scheme, sut.Addr()))
+				if err != nil {
+					return err
+				}
+				defer res.Body.Close()
+				b, err := io.ReadAll(res.Body)
+				body = string(b)
+				return err
+			})).Add(sut).Run()
+			return code, body
+		}
+	)
+
+	t.Run("serve http", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.HTTPServer("http", &http.Server{Addr: "127.0.0.1:0", Handler: hello})
+		)
+
+		// act
+		code, body := runAndGet(t, sut, http.DefaultClient, "http")
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, "hello HTTP/1.1", body)
+		assert.Equal(t, "http", sut.Name())
+	})
+
+	t.Run("fail setup when the address is in use", func(t *testing.T) {
+		// arrange
+		var (
+			taken, err = net.Listen("tcp", "127.0.0.1:0")
+			sut        = anchor.HTTPServer("http", &http.Server{Addr: taken.Addr().String(), Handler: hello})
+		)
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = taken.Close() })
+
+		a := anchor.New(anchor.ManualWire()).Add(sut)
+
+		// act
+		code := a.Run()
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+		// Close of the server is not an error, when it was not set up
+		assert.Equal(t, 1, len(a.Err().(interface{ Unwrap() []error }).Unwrap()))
+		assert.Truef(t, strings.HasPrefix(a.Err().Error(), `Setup "http"`), "err: %v", a.Err())
+	})
+
+	t.Run("fail start and probe when not set up", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.HTTPServer("http", &http.Server{Addr: "127.0.0.1:0", Handler: hello})
+		)
+
+		// act
+		startErr := sut.Start(t.Context())
+		probeErr := sut.Probe(t.Context())
+
+		// assert
+		assert.Error(t, startErr)
+		assert.Error(t, probeErr)
+		assert.NoError(t, sut.Close(t.Context()))
+	})
+
+	t.Run("serve h2c", func(t *testing.T) {
+		// arrange
+		var (
+			protocols = new(http.Protocols)
+			client    = &http.Client{Transport: &http.Transport{Protocols: protocols}}
+			sut       = anchor.HTTPServer("http", &http.Server{Addr: "127.0.0.1:0", Handler: hello}, anchor.WithHTTPH2C())
+		)
+		protocols.SetUnencryptedHTTP2(true)
+
+		// act
+		code, body := runAndGet(t, sut, client, "http")
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, "hello HTTP/2.0", body)
+	})
+
+	t.Run("keep http2 over tls with h2c", func(t *testing.T) {
+		// arrange
+		var (
+			ts  = httptest.NewTLSServer(hello)
+			sut = anchor.HTTPServer("https", &http.Server{
+				Addr:      "127.0.0.1:0",
+				Handler:   hello,
+				TLSConfig: &tls.Config{Certificates: ts.TLS.Certificates},
+			}, anchor.WithHTTPTLS("", ""), anchor.WithHTTPH2C())
+			transport = ts.Client().Transport.(*http.Transport).Clone()
+		)
+		t.Cleanup(ts.Close)
+		transport.ForceAttemptHTTP2 = true
+
+		// act
+		code, body := runAndGet(t, sut, &http.Client{Transport: transport}, "https")
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, "hello HTTP/2.0", body)
+	})
+
+	t.Run("keep the protocols of the server with h2c", func(t *testing.T) {
+		// arrange
+		var (
+			protocols = new(http.Protocols)
+			server    = &http.Server{Addr: "127.0.0.1:0", Handler: hello, Protocols: protocols}
+			sut       = anchor.HTTPServer("http", server, anchor.WithHTTPH2C())
+		)
+		protocols.SetHTTP1(false)
+
+		// act
+		err := sut.Setup(t.Context())
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, false, server.Protocols.HTTP1())
+		assert.Equal(t, true, server.Protocols.UnencryptedHTTP2())
+		assert.Equal(t, false, protocols.UnencryptedHTTP2())
+		assert.NoError(t, sut.Close(t.Context()))
+	})
+
+	t.Run("serve tls", func(t *testing.T) {
+		// arrange
+		var (
+			ts  = httptest.NewTLSServer(hello)
+			sut = anchor.HTTPServer("https", &http.Server{
+				Addr:      "127.0.0.1:0",
+				Handler:   hello,
+				TLSConfig: &tls.Config{Certificates: ts.TLS.Certificates},
+			}, anchor.WithHTTPTLS("", ""))
+		)
+		t.Cleanup(ts.Close)
+
+		// act
+		code, body := runAndGet(t, sut, ts.Client(), "https")
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, "hello HTTP/1.1", body)
+	})
+
+	t.Run("close the listener when not started", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.HTTPServer("http", &http.Server{Addr: "127.0.0.1:0", Handler: hello})
+		)
+		assert.NoError(t, sut.Setup(t.Context()))
+		addr := sut.Addr().String()
+
+		// act
+		err := sut.Close(t.Context())
+
+		// assert
+		assert.NoError(t, err)
+		_, dialErr := net.Dial("tcp", addr)
+		assert.Error(t, dialErr)
+	})
+
+	t.Run("close when shutdown times out", func(t *testing.T) {
+		// arrange
+		var (
+			release = make(chan struct{})
+			started = make(chan struct{})
+			sut     = anchor.HTTPServer("http", &http.Server{Addr: "127.0.0.1:0", Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-release
+			})})
+			requestErr = make(chan error, 1)
+		)
+		t.Cleanup(func() { close(release) })
+		assert.NoError(t, sut.Setup(t.Context()))
+		go func() { _ = sut.Start(t.Context()) }()
+		go func() {
+			_, err := http.Get(fmt.Sprintf("http://%s/", sut.Addr()))
+			requestErr <- err
+		}()
+		<-started
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		// act
+		err := sut.Close(ctx)
+
+		// assert
+		assert.Truef(t, errors.Is(err, context.DeadlineExceeded), "close error: %v", err)
+		assert.Error(t, <-requestErr)
+	})
+}
@@ -0,0 +1,73 @@
+// Package activation reads the listeners passed by systemd socket activation.
+package activation
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FirstFD is the first file descriptor passed by systemd.
+const FirstFD = 3
+
+// Listeners passed to this process, by the name in LISTEN_FDNAMES.
+// It is empty when the process is not socket activated.
+func Listeners() (map[string][]net.Listener, error) {
+	return FromEnv(os.Getenv, os.Getpid(), FirstFD)
+}
+
+// FromEnv returns the listeners in the variables of getenv, when LISTEN_PID is pid.
+// The listeners are at the file descriptors from firstFD.
+func FromEnv(getenv func(string) string, pid, firstFD int) (map[string][]net.Listener, error) {
+	listeners := make(map[string][]net.Listener)
+
+	count := getenv("LISTEN_FDS")
+	if count == "" {
+		return listeners, nil
+	}
+
+	if listenPID := getenv("LISTEN_PID"); listenPID != "" && listenPID != strconv.Itoa(pid) {
+		// the variables were meant for another process
+		return listeners, nil
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", count)
+	}
+
+	var names []string
+	if fdNames := getenv("LISTEN_FDNAMES"); fdNames != "" {
+		names = strings.Split(fdNames, ":")
+	}
+
+	for i := range n {
+		name := "unknown"
+		if i < len(names) {
+			name = names[i]
+		}
+
+		f := os.NewFile(uintptr(firstFD+i), name)
+		// FileListener duplicates the file descriptor, so the original is closed
+		listener, err := net.FileListener(f)
+		_ = f.Close()
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("listener %q at fd %d: %w", name, firstFD+i, err)
+		}
+
+		listeners[name] = append(listeners[name], listener)
+	}
+
+	return listeners, nil
+}
+
+func closeAll(listeners map[string][]net.Listener) {
+	for _, named := range listeners {
+		for _, listener := range named {
+			_ = listener.Close()
+		}
+	}
+}
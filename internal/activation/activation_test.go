@@ -0,0 +1,119 @@
+//go:build unix
+
+package activation_test
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/kyuff/anchor/internal/activation"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	var (
+		pid    = 4242
+		getenv = func(env map[string]string) func(string) string {
+			return func(key string) string { return env[key] }
+		}
+		// listenerFD returns a file descriptor of a listener, that is owned by FromEnv
+		listenerFD = func(t *testing.T) (int, string) {
+			t.Helper()
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			assert.NoError(t, err)
+			t.Cleanup(func() { _ = l.Close() })
+			f, err := l.(*net.TCPListener).File()
+			assert.NoError(t, err)
+			defer f.Close()
+			fd, err := syscall.Dup(int(f.Fd()))
+			assert.NoError(t, err)
+			return fd, l.Addr().String()
+		}
+	)
+
+	t.Run("return nothing when not activated", func(t *testing.T) {
+		// act
+		got, err := activation.FromEnv(getenv(nil), pid, activation.FirstFD)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(got))
+	})
+
+	t.Run("return nothing for another process", func(t *testing.T) {
+		// act
+		got, err := activation.FromEnv(getenv(map[string]string{
+			"LISTEN_FDS": "1",
+			"LISTEN_PID": "1",
+		}), pid, activation.FirstFD)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(got))
+	})
+
+	t.Run("return listeners by name", func(t *testing.T) {
+		// arrange
+		fd, addr := listenerFD(t)
+
+		// act
+		got, err := activation.FromEnv(getenv(map[string]string{
+			"LISTEN_FDS":     "1",
+			"LISTEN_PID":     strconv.Itoa(pid),
+			"LISTEN_FDNAMES": "http",
+		}), pid, fd)
+
+		// assert
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(got["http"])) {
+			assert.Equal(t, addr, got["http"][0].Addr().String())
+			assert.NoError(t, got["http"][0].Close())
+		}
+	})
+
+	t.Run("name listeners unknown without names", func(t *testing.T) {
+		// arrange
+		fd, _ := listenerFD(t)
+
+		// act
+		got, err := activation.FromEnv(getenv(map[string]string{
+			"LISTEN_FDS": "1",
+		}), pid, fd)
+
+		// assert
+		assert.NoError(t, err)
+		if assert.Equal(t, 1, len(got["unknown"])) {
+			assert.NoError(t, got["unknown"][0].Close())
+		}
+	})
+
+	t.Run("fail on invalid count", func(t *testing.T) {
+		// act
+		_, err := activation.FromEnv(getenv(map[string]string{
+			"LISTEN_FDS": "many",
+		}), pid, activation.FirstFD)
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("fail when the file is not a listener", func(t *testing.T) {
+		// arrange
+		f, err := os.CreateTemp(t.TempDir(), "not-a-socket")
+		assert.NoError(t, err)
+		defer f.Close()
+		fd, err := syscall.Dup(int(f.Fd()))
+		assert.NoError(t, err)
+
+		// act
+		_, err = activation.FromEnv(getenv(map[string]string{
+			"LISTEN_FDS": "1",
+		}), pid, fd)
+
+		// assert
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,114 @@
+// Package clock lets the Anchor measure time with a Clock that can be replaced in tests.
+package clock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
+// Real is the Clock of the time package.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (Real) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries the Clock.
+func NewContext(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Clock of ctx or Real if it has none.
+func FromContext(ctx context.Context) Clock {
+	if c, ok := ctx.Value(contextKey{}).(Clock); ok {
+		return c
+	}
+
+	return Real{}
+}
+
+// WithTimeout is context.WithTimeout measured by the Clock.
+func WithTimeout(ctx context.Context, c Clock, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := c.(Real); ok {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	deadline := c.Now().Add(timeout)
+	if parent, ok := ctx.Deadline(); ok && parent.Before(deadline) {
+		deadline = parent
+	}
+
+	// the cause is the deadline, so contexts derived from inner see it as well
+	inner, cancel := context.WithCancelCause(ctx)
+	tc := &timeoutContext{
+		Context:  inner,
+		deadline: deadline,
+		done:     make(chan struct{}),
+	}
+
+	stop := c.AfterFunc(timeout, func() {
+		cancel(context.DeadlineExceeded)
+	})
+
+	// Done is not the channel of inner, so a derived context can not attach to inner,
+	// which has the error Canceled, and waits for Err of the timeoutContext instead.
+	context.AfterFunc(inner, func() {
+		err := inner.Err()
+		if errors.Is(context.Cause(inner), context.DeadlineExceeded) {
+			err = context.DeadlineExceeded
+		}
+
+		tc.mu.Lock()
+		tc.err = err
+		tc.mu.Unlock()
+		close(tc.done)
+	})
+
+	return tc, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+type timeoutContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+func (c *timeoutContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *timeoutContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *timeoutContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
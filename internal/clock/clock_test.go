@@ -0,0 +1,104 @@
+package clock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor/internal/assert"
+	"github.com/kyuff/anchor/internal/clock"
+)
+
+// manual fires AfterFunc when fire is called.
+type manual struct {
+	now  time.Time
+	fire func()
+}
+
+func (m *manual) Now() time.Time                         { return m.now }
+func (m *manual) After(d time.Duration) <-chan time.Time { return nil }
+func (m *manual) AfterFunc(d time.Duration, f func()) func() bool {
+	m.fire = f
+	return func() bool { return true }
+}
+
+func TestClock(t *testing.T) {
+	t.Run("default to real clock", func(t *testing.T) {
+		// act
+		got := clock.FromContext(t.Context())
+
+		// assert
+		assert.Equal(t, clock.Clock(clock.Real{}), got)
+	})
+
+	t.Run("carry clock in context", func(t *testing.T) {
+		// arrange
+		var (
+			c = &manual{}
+		)
+
+		// act
+		got := clock.FromContext(clock.NewContext(t.Context(), c))
+
+		// assert
+		assert.Equal(t, clock.Clock(c), got)
+	})
+
+	t.Run("time out when clock fires", func(t *testing.T) {
+		// arrange
+		var (
+			now = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			c   = &manual{now: now}
+		)
+
+		ctx, cancel := clock.WithTimeout(t.Context(), c, time.Hour)
+		defer cancel()
+
+		// act
+		c.fire()
+
+		// assert
+		<-ctx.Done()
+		assert.Truef(t, errors.Is(ctx.Err(), context.DeadlineExceeded), "unexpected error: %v", ctx.Err())
+		deadline, ok := ctx.Deadline()
+		assert.Truef(t, ok, "no deadline")
+		assert.Equal(t, now.Add(time.Hour), deadline)
+	})
+
+	t.Run("time out derived contexts when clock fires", func(t *testing.T) {
+		// arrange
+		var (
+			c = &manual{}
+		)
+
+		ctx, cancel := clock.WithTimeout(t.Context(), c, time.Hour)
+		defer cancel()
+		derived, cancelDerived := context.WithCancel(ctx)
+		defer cancelDerived()
+
+		// act
+		c.fire()
+
+		// assert
+		<-derived.Done()
+		assert.Truef(t, errors.Is(derived.Err(), context.DeadlineExceeded), "unexpected error: %v", derived.Err())
+		assert.Truef(t, errors.Is(context.Cause(derived), context.DeadlineExceeded), "unexpected cause: %v", context.Cause(derived))
+	})
+
+	t.Run("cancel before timeout", func(t *testing.T) {
+		// arrange
+		var (
+			c = &manual{}
+		)
+
+		ctx, cancel := clock.WithTimeout(t.Context(), c, time.Hour)
+
+		// act
+		cancel()
+
+		// assert
+		<-ctx.Done()
+		assert.Truef(t, errors.Is(ctx.Err(), context.Canceled), "unexpected error: %v", ctx.Err())
+	})
+}
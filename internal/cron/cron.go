@@ -0,0 +1,260 @@
+// Package cron parses cron expressions and finds the times they match.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxYears is how far Next looks for a match, before it gives up.
+const maxYears = 5
+
+// Schedule of a cron expression.
+type Schedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// when both day fields are restricted, a time matches either of them
+	domStar, dowStar bool
+	// Location is set by a CRON_TZ= or TZ= prefix. It is nil otherwise.
+	Location *time.Location
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	seconds = field{name: "second", min: 0, max: 59}
+	minutes = field{name: "minute", min: 0, max: 59}
+	hours   = field{name: "hour", min: 0, max: 23}
+	doms    = field{name: "day of month", min: 1, max: 31}
+	months  = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is Sunday as well as 0
+	dows = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 0 1 1 *",
+	"@annually": "0 0 0 1 1 *",
+	"@monthly":  "0 0 0 1 * *",
+	"@weekly":   "0 0 0 * * 0",
+	"@daily":    "0 0 0 * * *",
+	"@midnight": "0 0 0 * * *",
+	"@hourly":   "0 0 * * * *",
+}
+
+// Parse a cron expression.
+//
+// It has 5 fields (minute, hour, day of month, month, day of week) or 6 fields with seconds first.
+// A field is * or ?, a list of values, ranges and steps like 1,5-10,*/15, or names like JAN or MON-FRI.
+// The macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported,
+// and so is a time zone prefix like CRON_TZ=Europe/Copenhagen.
+func Parse(expr string) (*Schedule, error) {
+	var (
+		s      = &Schedule{}
+		fields = strings.Fields(expr)
+	)
+
+	if len(fields) > 0 {
+		if tz, ok := cutPrefix(fields[0], "CRON_TZ=", "TZ="); ok {
+			loc, err := time.LoadLocation(tz)
+			if err != nil {
+				return nil, fmt.Errorf("cron %q: time zone: %w", expr, err)
+			}
+			s.Location = loc
+			fields = fields[1:]
+		}
+	}
+
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		macro, ok := macros[strings.ToLower(fields[0])]
+		if !ok {
+			return nil, fmt.Errorf("cron %q: unknown macro %s", expr, fields[0])
+		}
+		fields = strings.Fields(macro)
+	}
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron %q: expected 5 or 6 fields, got %d", expr, len(fields))
+	}
+
+	var err error
+	parse := func(value string, f field) uint64 {
+		bits, fieldErr := f.parse(value)
+		err = errors.Join(err, fieldErr)
+		return bits
+	}
+
+	s.second = parse(fields[0], seconds)
+	s.minute = parse(fields[1], minutes)
+	s.hour = parse(fields[2], hours)
+	s.dom = parse(fields[3], doms)
+	s.month = parse(fields[4], months)
+	s.dow = parse(fields[5], dows)
+	if err != nil {
+		return nil, fmt.Errorf("cron %q: %w", expr, err)
+	}
+
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = isStar(fields[3])
+	s.dowStar = isStar(fields[5])
+
+	return s, nil
+}
+
+func cutPrefix(s string, prefixes ...string) (string, bool) {
+	for _, prefix := range prefixes {
+		if after, ok := strings.CutPrefix(s, prefix); ok {
+			return after, true
+		}
+	}
+
+	return "", false
+}
+
+func isStar(value string) bool {
+	return value == "*" || value == "?"
+}
+
+// parse a field to a bit set of the values it matches.
+func (f field) parse(value string) (uint64, error) {
+	var bits uint64
+	for part := range strings.SplitSeq(value, ",") {
+		first, last, step, err := f.parseRange(part)
+		if err != nil {
+			return 0, fmt.Errorf("%s %q: %w", f.name, part, err)
+		}
+
+		for v := first; v <= last; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+func (f field) parseRange(part string) (first, last, step int, err error) {
+	span, stepText, hasStep := strings.Cut(part, "/")
+
+	step = 1
+	if hasStep {
+		step, err = strconv.Atoi(stepText)
+		if err != nil || step < 1 {
+			return 0, 0, 0, fmt.Errorf("invalid step %q", stepText)
+		}
+	}
+
+	if isStar(span) {
+		return f.min, f.max, step, nil
+	}
+
+	firstText, lastText, isRange := strings.Cut(span, "-")
+	first, err = f.value(firstText)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	switch {
+	case isRange:
+		last, err = f.value(lastText)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+	case hasStep:
+		// 5/10 is from 5 to the end
+		last = f.max
+	default:
+		last = first
+	}
+
+	if last < first {
+		return 0, 0, 0, fmt.Errorf("range from %d to %d is empty", first, last)
+	}
+
+	return first, last, step, nil
+}
+
+func (f field) value(text string) (int, error) {
+	if v, ok := f.names[strings.ToLower(text)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%d is not within %d-%d", v, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// Next returns the first time after t that matches the Schedule,
+// in the Location of the Schedule or else of t.
+// It returns the zero time if there is no match within 5 years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.Location != nil {
+		t = t.In(s.Location)
+	}
+
+	var (
+		loc   = t.Location()
+		limit = t.Year() + maxYears
+	)
+
+	t = t.Truncate(time.Second).Add(time.Second)
+
+	for t.Year() <= limit {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !has(s.hour, t.Hour()):
+			// step in absolute time, as a local hour can be skipped or repeated by daylight saving time
+			t = t.Truncate(time.Minute).Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !has(s.minute, t.Minute()):
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		case !has(s.second, t.Second()):
+			t = t.Add(time.Second)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	var (
+		dom = has(s.dom, t.Day())
+		dow = has(s.dow, int(t.Weekday()))
+	)
+
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<v) != 0
+}
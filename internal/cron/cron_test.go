@@ -0,0 +1,160 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor/internal/assert"
+	"github.com/kyuff/anchor/internal/cron"
+)
+
+func TestParse(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * FOO *",
+		"@fortnightly",
+		"CRON_TZ=Nowhere/Special * * * * *",
+	} {
+		t.Run("fail on "+expr, func(t *testing.T) {
+			// act
+			_, err := cron.Parse(expr)
+
+			// assert
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	var (
+		copenhagen, _ = time.LoadLocation("Europe/Copenhagen")
+		at            = func(value string) time.Time {
+			t, err := time.ParseInLocation(time.DateTime, value, time.UTC)
+			if err != nil {
+				panic(err)
+			}
+			return t
+		}
+	)
+
+	testCases := []struct {
+		name string
+		expr string
+		from time.Time
+		next []time.Time
+	}{
+		{
+			name: "every minute",
+			expr: "* * * * *",
+			from: at("2025-01-01 10:00:30"),
+			next: []time.Time{at("2025-01-01 10:01:00"), at("2025-01-01 10:02:00")},
+		},
+		{
+			name: "seconds field",
+			expr: "0 */5 * * * *",
+			from: at("2025-01-01 10:03:00"),
+			next: []time.Time{at("2025-01-01 10:05:00"), at("2025-01-01 10:10:00")},
+		},
+		{
+			name: "list and range",
+			expr: "0 8-9,17 * * *",
+			from: at("2025-01-01 09:00:00"),
+			next: []time.Time{at("2025-01-01 17:00:00"), at("2025-01-02 08:00:00")},
+		},
+		{
+			name: "step from a value",
+			expr: "50/5 * * * *",
+			from: at("2025-01-01 10:00:00"),
+			next: []time.Time{at("2025-01-01 10:50:00"), at("2025-01-01 10:55:00"), at("2025-01-01 11:50:00")},
+		},
+		{
+			name: "names",
+			expr: "0 9 * feb MON-wed",
+			from: at("2025-01-15 00:00:00"),
+			next: []time.Time{at("2025-02-03 09:00:00"), at("2025-02-04 09:00:00"), at("2025-02-05 09:00:00"), at("2025-02-10 09:00:00")},
+		},
+		{
+			name: "sunday as 7",
+			expr: "0 0 * * 7",
+			from: at("2025-01-01 00:00:00"),
+			next: []time.Time{at("2025-01-05 00:00:00")},
+		},
+		{
+			name: "day of month or day of week",
+			expr: "0 0 13 * 5",
+			from: at("2025-06-01 00:00:00"),
+			next: []time.Time{at("2025-06-06 00:00:00"), at("2025-06-13 00:00:00"), at("2025-06-20 00:00:00")},
+		},
+		{
+			name: "leap day",
+			expr: "0 0 29 2 *",
+			from: at("2025-01-01 00:00:00"),
+			next: []time.Time{at("2028-02-29 00:00:00")},
+		},
+		{
+			name: "macro",
+			expr: "@monthly",
+			from: at("2025-01-31 12:00:00"),
+			next: []time.Time{at("2025-02-01 00:00:00"), at("2025-03-01 00:00:00")},
+		},
+		{
+			name: "time zone",
+			expr: "CRON_TZ=Europe/Copenhagen 0 9 * * *",
+			from: at("2025-01-01 00:00:00"),
+			next: []time.Time{at("2025-01-01 08:00:00"), at("2025-01-02 08:00:00")},
+		},
+		{
+			name: "skip the hour lost to daylight saving time",
+			expr: "TZ=Europe/Copenhagen 30 2 * * *",
+			from: at("2025-03-29 12:00:00"),
+			next: []time.Time{at("2025-03-31 00:30:00")},
+		},
+		{
+			name: "no match",
+			expr: "0 0 30 2 *",
+			from: at("2025-01-01 00:00:00"),
+			next: []time.Time{{}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// arrange
+			sut, err := cron.Parse(tc.expr)
+			assert.NoError(t, err)
+
+			// act
+			var got []time.Time
+			for from := tc.from; len(got) < len(tc.next); {
+				from = sut.Next(from)
+				got = append(got, from.UTC())
+			}
+
+			// assert
+			for i := range tc.next {
+				assert.Equal(t, tc.next[i], got[i])
+			}
+		})
+	}
+
+	t.Run("keep the location of the time", func(t *testing.T) {
+		// arrange
+		sut, err := cron.Parse("0 9 * * *")
+		assert.NoError(t, err)
+
+		// act
+		got := sut.Next(time.Date(2025, 7, 1, 0, 0, 0, 0, copenhagen))
+
+		// assert
+		assert.Equal(t, time.Date(2025, 7, 1, 9, 0, 0, 0, copenhagen), got)
+	})
+}
@@ -1,6 +1,9 @@
 package decorate
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 type setupper interface {
 	starter
@@ -43,9 +46,26 @@ type Component struct {
 	setup func(ctx context.Context) error
 	close func(ctx context.Context) error
 
+	mu    sync.RWMutex
 	inner starter
 }
 
+// load the inner component, which Setup may be storing concurrently
+// when it is abandoned on a timeout.
+func (c *Component) load() starter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.inner
+}
+
+func (c *Component) store(inner starter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inner = inner
+}
+
 func (c *Component) Start(ctx context.Context) error {
 	return c.start(ctx)
 }
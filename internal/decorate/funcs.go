@@ -0,0 +1,24 @@
+package decorate
+
+import "context"
+
+// Funcs creates a Component of the functions. A nil function does nothing.
+func Funcs(name string, setup, start, probe, close func(ctx context.Context) error) *Component {
+	return &Component{
+		name: func() string {
+			return name
+		},
+		setup: orNoop(setup),
+		start: orNoop(start),
+		probe: orNoop(probe),
+		close: orNoop(close),
+	}
+}
+
+func orNoop(fn func(ctx context.Context) error) func(ctx context.Context) error {
+	if fn == nil {
+		return func(_ context.Context) error { return nil }
+	}
+
+	return fn
+}
@@ -0,0 +1,64 @@
+package decorate_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kyuff/anchor/internal/assert"
+	"github.com/kyuff/anchor/internal/decorate"
+)
+
+func TestFuncs(t *testing.T) {
+	t.Run("call the functions", func(t *testing.T) {
+		// arrange
+		var (
+			called []string
+			record = func(phase string) func(ctx context.Context) error {
+				return func(ctx context.Context) error {
+					called = append(called, phase)
+					return nil
+				}
+			}
+		)
+
+		// act
+		sut := decorate.Funcs("TEST NAME", record("setup"), record("start"), record("probe"), record("close"))
+
+		// assert
+		assert.NoError(t, sut.Setup(t.Context()))
+		assert.NoError(t, sut.Start(t.Context()))
+		assert.NoError(t, sut.Probe(t.Context()))
+		assert.NoError(t, sut.Close(t.Context()))
+		assert.Equal(t, "TEST NAME", sut.Name())
+		assert.EqualSlice(t, []string{"setup", "start", "probe", "close"}, called)
+	})
+
+	t.Run("return the errors", func(t *testing.T) {
+		// arrange
+		var (
+			expected = errors.New("fail")
+			fail     = func(ctx context.Context) error { return expected }
+		)
+
+		// act
+		sut := decorate.Funcs("TEST NAME", fail, fail, fail, fail)
+
+		// assert
+		assert.Equal(t, expected, sut.Setup(t.Context()))
+		assert.Equal(t, expected, sut.Start(t.Context()))
+		assert.Equal(t, expected, sut.Probe(t.Context()))
+		assert.Equal(t, expected, sut.Close(t.Context()))
+	})
+
+	t.Run("do nothing for nil functions", func(t *testing.T) {
+		// act
+		sut := decorate.Funcs("TEST NAME", nil, nil, nil, nil)
+
+		// assert
+		assert.NoError(t, sut.Setup(t.Context()))
+		assert.NoError(t, sut.Start(t.Context()))
+		assert.NoError(t, sut.Probe(t.Context()))
+		assert.NoError(t, sut.Close(t.Context()))
+	})
+}
@@ -5,7 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"sync/atomic"
-	"time"
+
+	"github.com/kyuff/anchor/internal/clock"
 )
 
 func makeComponent[T starter](c *Component, name string, setup func() (T, error), probe func(ctx context.Context) error) *Component {
@@ -28,12 +29,12 @@ func makeSetup[T starter](c *Component, setup func() (T, error)) func(ctx contex
 			return fmt.Errorf("nil setup func")
 		}
 
-		var err error
-		c.inner, err = setup()
+		inner, err := setup()
+		c.store(inner)
 		if err != nil {
 			return err
 		}
-		if isNil(c.inner) {
+		if isNil(inner) {
 			err = fmt.Errorf("nil make component")
 			return err
 		}
@@ -50,17 +51,17 @@ func makeSetup[T starter](c *Component, setup func() (T, error)) func(ctx contex
 			return nil
 		}
 
-		return fn(c.inner)
+		return fn(inner)
 	}
 }
 
 func makeSetupCall(c *Component) func(ctx context.Context) error {
-	if c, ok := c.inner.(setupper); ok {
+	if c, ok := c.load().(setupper); ok {
 		return func(_ context.Context) error {
 			return c.Setup()
 		}
 	}
-	if c, ok := c.inner.(contextSetupper); ok {
+	if c, ok := c.load().(contextSetupper); ok {
 		return c.Setup
 	}
 	return func(ctx context.Context) error { return nil }
@@ -68,7 +69,8 @@ func makeSetupCall(c *Component) func(ctx context.Context) error {
 
 func makeClose(c *Component) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
-		if isNil(c.inner) {
+		inner := c.load()
+		if isNil(inner) {
 			return fmt.Errorf("nil make component")
 		}
 
@@ -84,24 +86,25 @@ func makeClose(c *Component) func(ctx context.Context) error {
 			return nil
 		}
 
-		return fn(c.inner)
+		return fn(inner)
 	}
 }
 
 func makeStart(c *Component, startTime *atomic.Int64) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
-		if isNil(c.inner) {
+		inner := c.load()
+		if isNil(inner) {
 			return fmt.Errorf("nil make component")
 		}
 
-		startTime.Store(time.Now().UnixMilli())
-		return c.inner.Start(ctx)
+		startTime.Store(clock.FromContext(ctx).Now().UnixMilli())
+		return inner.Start(ctx)
 	}
 }
 
 func makeProbe(startTime *atomic.Int64, probe func(ctx context.Context) error) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
-		if !probeIsReady(startTime.Load()) {
+		if !probeIsReady(startTime.Load(), clock.FromContext(ctx).Now()) {
 			return fmt.Errorf("component is not started yet")
 		}
 
@@ -112,7 +115,7 @@ func makeProbe(startTime *atomic.Int64, probe func(ctx context.Context) error) f
 func probeInner(c *Component) func(ctx context.Context) error {
 	return func(ctx context.Context) error {
 
-		if c, ok := c.inner.(contextProber); ok {
+		if c, ok := c.load().(contextProber); ok {
 			return c.Probe(ctx)
 		}
 
@@ -128,10 +131,10 @@ func makeName(name string) func() string {
 
 func makeNameCall(c *Component) func() string {
 	return func() string {
-		if c, ok := c.inner.(namer); ok {
+		if c, ok := c.load().(namer); ok {
 			return c.Name()
 		}
-		return fmt.Sprintf("%T", c.inner)
+		return fmt.Sprintf("%T", c.load())
 	}
 }
 
@@ -4,11 +4,10 @@ import "time"
 
 var probeDelay = (time.Millisecond * 15).Milliseconds()
 
-func probeIsReady(startTime int64) bool {
+func probeIsReady(startTime int64, now time.Time) bool {
 	var (
-		now       = time.Now().UnixMilli()
 		readyTime = startTime + probeDelay
 	)
 
-	return startTime > 0 && readyTime < now
+	return startTime > 0 && readyTime < now.UnixMilli()
 }
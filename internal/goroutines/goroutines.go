@@ -0,0 +1,156 @@
+// Package goroutines reads the goroutine profile of the process.
+package goroutines
+
+import (
+	"bytes"
+	"regexp"
+	"runtime/pprof"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var labelRegexp = regexp.MustCompile(`("(?:[^"\\]|\\.)*"):("(?:[^"\\]|\\.)*")`)
+
+// Group is one or more goroutines with the same stack and labels.
+type Group struct {
+	Count  int
+	Labels map[string]string
+	Stack  string
+}
+
+// Profile returns the goroutines of the process, except those taking the profile.
+func Profile() []Group {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		return nil
+	}
+
+	var groups []Group
+	for _, g := range Parse(buf.String()) {
+		if strings.Contains(g.Stack, "runtime/pprof.writeGoroutine") {
+			continue
+		}
+
+		groups = append(groups, g)
+	}
+
+	return groups
+}
+
+// Parse reads a goroutine profile in the debug=1 text format.
+func Parse(profile string) []Group {
+	var groups []Group
+	for group := range strings.SplitSeq(profile, "\n\n") {
+		var (
+			lines  = strings.Split(strings.TrimSpace(group), "\n")
+			labels map[string]string
+			stack  []string
+		)
+		if strings.HasPrefix(lines[0], "goroutine profile:") {
+			// the header is not separated from the first group
+			lines = lines[1:]
+		}
+
+		if len(lines) < 2 {
+			continue
+		}
+
+		count, _, ok := strings.Cut(lines[0], " @ ")
+		if !ok {
+			continue
+		}
+
+		for _, line := range lines[1:] {
+			if l, ok := strings.CutPrefix(line, "# labels: "); ok {
+				labels = parseLabels(l)
+				continue
+			}
+
+			// #	0x4a8ee4	main.foo+0x24	/path/file.go:12
+			fields := strings.Fields(strings.TrimPrefix(line, "#"))
+			if len(fields) >= 3 {
+				stack = append(stack, fields[1]+"\n\t"+fields[2])
+			}
+		}
+
+		n, _ := strconv.Atoi(count)
+		groups = append(groups, Group{
+			Count:  n,
+			Labels: labels,
+			Stack:  strings.Join(stack, "\n"),
+		})
+	}
+
+	return groups
+}
+
+// Labeled returns the groups with the label set to value.
+func Labeled(groups []Group, key, value string) []Group {
+	var labeled []Group
+	for _, g := range groups {
+		if g.Labels[key] == value {
+			labeled = append(labeled, g)
+		}
+	}
+
+	return labeled
+}
+
+// Count of goroutines in the groups.
+func Count(groups []Group) int {
+	var n int
+	for _, g := range groups {
+		n += g.Count
+	}
+
+	return n
+}
+
+func parseLabels(s string) map[string]string {
+	var labels = make(map[string]string)
+	for _, match := range labelRegexp.FindAllStringSubmatch(s, -1) {
+		key, err := strconv.Unquote(match[1])
+		if err != nil {
+			continue
+		}
+
+		value, err := strconv.Unquote(match[2])
+		if err != nil {
+			continue
+		}
+
+		labels[key] = value
+	}
+
+	return labels
+}
+
+// Leftover returns the groups in after that have more goroutines than
+// the group with the same stack and labels in before.
+func Leftover(before, after []Group) []Group {
+	var counts = make(map[string]int)
+	for _, g := range before {
+		counts[key(g)] += g.Count
+	}
+
+	var leftover []Group
+	for _, g := range after {
+		if n := g.Count - counts[key(g)]; n > 0 {
+			g.Count = n
+			leftover = append(leftover, g)
+		}
+	}
+
+	return leftover
+}
+
+func key(g Group) string {
+	var labels = make([]string, 0, len(g.Labels))
+	for k, v := range g.Labels {
+		labels = append(labels, strconv.Quote(k)+":"+strconv.Quote(v))
+	}
+	slices.Sort(labels)
+
+	return strings.Join(labels, ",") + "\n" + g.Stack
+}
@@ -0,0 +1,93 @@
+package goroutines_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/kyuff/anchor/internal/assert"
+	"github.com/kyuff/anchor/internal/goroutines"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("read groups with labels", func(t *testing.T) {
+		// arrange
+		var (
+			profile = `goroutine profile: total 3
+2 @ 0x4a8ee4 0x4a8f10
+# labels: {"anchor":"1", "anchor.component":"a \"b\""}
+#	0x4a8ee4	main.foo+0x24	/src/foo.go:12
+#	0x4a8f10	main.bar+0x10	/src/bar.go:7
+
+1 @ 0x4a8ee4
+#	0x4a8ee4	main.foo+0x24	/src/foo.go:12
+`
+		)
+
+		// act
+		got := goroutines.Parse(profile)
+
+		// assert
+		assert.Equal(t, 2, len(got))
+		assert.Equal(t, 2, got[0].Count)
+		assert.Equal(t, "1", got[0].Labels["anchor"])
+		assert.Equal(t, `a "b"`, got[0].Labels["anchor.component"])
+		assert.Equal(t, "main.foo+0x24\n\t/src/foo.go:12\nmain.bar+0x10\n\t/src/bar.go:7", got[0].Stack)
+		assert.Equal(t, 0, len(got[1].Labels))
+		assert.Equal(t, 3, goroutines.Count(got))
+		assert.Equal(t, 1, len(goroutines.Labeled(got, "anchor", "1")))
+	})
+}
+
+func TestLeftover(t *testing.T) {
+	t.Run("return the goroutines that are not in before", func(t *testing.T) {
+		// arrange
+		var (
+			before = []goroutines.Group{
+				{Count: 1, Stack: "a"},
+				{Count: 2, Stack: "b"},
+			}
+			after = []goroutines.Group{
+				{Count: 1, Stack: "a"},
+				{Count: 3, Stack: "b"},
+				{Count: 1, Stack: "a", Labels: map[string]string{"k": "v"}},
+				{Count: 1, Stack: "c"},
+			}
+		)
+
+		// act
+		got := goroutines.Leftover(before, after)
+
+		// assert
+		if assert.Equal(t, 3, len(got)) {
+			assert.Equal(t, "b", got[0].Stack)
+			assert.Equal(t, 1, got[0].Count)
+			assert.Equal(t, "v", got[1].Labels["k"])
+			assert.Equal(t, "c", got[2].Stack)
+		}
+	})
+}
+
+func TestProfile(t *testing.T) {
+	t.Run("find labeled goroutines", func(t *testing.T) {
+		// arrange
+		var (
+			release = make(chan struct{})
+			started = make(chan struct{})
+		)
+		pprof.Do(t.Context(), pprof.Labels("goroutines.test", t.Name()), func(context.Context) {
+			go func() {
+				close(started)
+				<-release
+			}()
+		})
+		<-started
+
+		// act
+		got := goroutines.Labeled(goroutines.Profile(), "goroutines.test", t.Name())
+
+		// assert
+		close(release)
+		assert.Equal(t, 1, goroutines.Count(got))
+	})
+}
@@ -10,8 +10,9 @@ import (
 
 type Noop struct{}
 
-func (Noop) InfofCtx(ctx context.Context, template string, args ...any)  {}
-func (Noop) ErrorfCtx(ctx context.Context, template string, args ...any) {}
+func (Noop) InfofCtx(ctx context.Context, template string, args ...any)                     {}
+func (Noop) ErrorfCtx(ctx context.Context, template string, args ...any)                    {}
+func (Noop) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {}
 
 func NewSlog(logger *slog.Logger) *Slog {
 	return &Slog{
@@ -44,3 +45,15 @@ func (log *Slog) ErrorfCtx(ctx context.Context, format string, args ...any) {
 	r := slog.NewRecord(time.Now(), slog.LevelError, fmt.Sprintf(format, args...), pcs[0])
 	_ = log.logger.Handler().Handle(ctx, r)
 }
+
+func (log *Slog) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !log.logger.Enabled(ctx, level) {
+		return
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:]) // skip [Callers, LogAttrs, the logging helper of the Anchor]
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.AddAttrs(attrs...)
+	_ = log.logger.Handler().Handle(ctx, r)
+}
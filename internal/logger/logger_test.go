@@ -100,6 +100,58 @@ func TestSlog(t *testing.T) {
 		})
 	})
 
+	t.Run("LogAttrs", func(t *testing.T) {
+		t.Run("log the attributes on the level", func(t *testing.T) {
+			// arrange
+			var (
+				handler = &HandlerMock{}
+				sut     = logger.NewSlog(slog.New(handler))
+			)
+
+			handler.EnabledFunc = func(contextMoqParam context.Context, level slog.Level) bool {
+				return true
+			}
+			handler.HandleFunc = func(ctx context.Context, record slog.Record) error {
+				assert.Equal(t, "hello", record.Message)
+				assert.Equal(t, slog.LevelWarn, record.Level)
+				var attrs []string
+				record.Attrs(func(attr slog.Attr) bool {
+					attrs = append(attrs, attr.String())
+					return true
+				})
+				assert.EqualSlice(t, []string{"component=db", "attempt=2"}, attrs)
+				return nil
+			}
+
+			// act
+			sut.LogAttrs(t.Context(), slog.LevelWarn, "hello", slog.String("component", "db"), slog.Int("attempt", 2))
+
+			// assert
+			assert.Equal(t, 1, len(handler.HandleCalls()))
+		})
+
+		t.Run("ignore disabled level", func(t *testing.T) {
+			// arrange
+			var (
+				handler = &HandlerMock{}
+				sut     = logger.NewSlog(slog.New(handler))
+			)
+
+			handler.EnabledFunc = func(contextMoqParam context.Context, level slog.Level) bool {
+				return level >= slog.LevelInfo
+			}
+			handler.HandleFunc = func(ctx context.Context, record slog.Record) error {
+				return nil
+			}
+
+			// act
+			sut.LogAttrs(t.Context(), slog.LevelDebug, "hello")
+
+			// assert
+			assert.Equal(t, 0, len(handler.HandleCalls()))
+		})
+	})
+
 	t.Run("Don't break on noop", func(t *testing.T) {
 		// arrange
 		var (
@@ -109,6 +161,7 @@ func TestSlog(t *testing.T) {
 		// act
 		sut.InfofCtx(t.Context(), "hello %s", "test")
 		sut.ErrorfCtx(t.Context(), "hello %s", "test")
+		sut.LogAttrs(t.Context(), slog.LevelDebug, "hello", slog.String("key", "value"))
 
 	})
 }
@@ -0,0 +1,214 @@
+package anchor
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/kyuff/anchor/internal/clock"
+)
+
+// Overlap decides what happens when a job is due while the previous run is not done.
+type Overlap int
+
+const (
+	// OverlapSkip skips the run that is due.
+	OverlapSkip Overlap = iota
+	// OverlapQueue runs once more right after the previous run is done.
+	OverlapQueue
+	// OverlapConcurrent runs next to the previous run.
+	OverlapConcurrent
+)
+
+// jobConfig is shared by Every and Cron.
+type jobConfig struct {
+	jitter    time.Duration
+	overlap   Overlap
+	timeout   time.Duration
+	onError   func(ctx context.Context, err error)
+	failAfter int
+}
+
+func defaultJobConfig() jobConfig {
+	return jobConfig{
+		overlap: OverlapSkip,
+		onError: func(ctx context.Context, err error) {},
+	}
+}
+
+// job calls fn on a schedule while it is started.
+type job struct {
+	fn  func(ctx context.Context) error
+	cfg *jobConfig
+	// next returns the time of the run after last. A zero time stops the schedule.
+	next   func(last, now time.Time) time.Time
+	failed chan error
+
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	running   int
+	queued    bool
+	closed    bool
+	runs      int
+	errors    int
+	inARow    int
+	cancelRun context.CancelFunc
+}
+
+func newJob(fn func(ctx context.Context) error, cfg *jobConfig, next func(last, now time.Time) time.Time) *job {
+	return &job{
+		fn:     fn,
+		cfg:    cfg,
+		next:   next,
+		failed: make(chan error, 1),
+	}
+}
+
+func (j *job) counts() (runs, errors int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.runs, j.errors
+}
+
+// start runs the job from the first time until ctx is done.
+func (j *job) start(ctx context.Context, first time.Time) error {
+	var (
+		clk            = clock.FromContext(ctx)
+		at             = first
+		runCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+	)
+
+	j.mu.Lock()
+	j.cancelRun = cancel
+	j.mu.Unlock()
+
+	for {
+		var due <-chan time.Time
+		if !at.IsZero() {
+			wait := at.Sub(clk.Now())
+			if j.cfg.jitter > 0 {
+				wait += rand.N(j.cfg.jitter)
+			}
+			due = clk.After(wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-j.failed:
+			return err
+		case <-due:
+		}
+
+		j.due(runCtx)
+		at = j.next(at, clk.Now())
+	}
+}
+
+// due starts a run by the Overlap policy.
+func (j *job) due(ctx context.Context) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		// start can be due once more while the Anchor closes
+		return
+	}
+
+	if j.running > 0 {
+		switch j.cfg.overlap {
+		case OverlapSkip:
+			return
+		case OverlapQueue:
+			j.queued = true
+			return
+		}
+	}
+
+	j.running++
+	j.wg.Add(1)
+	go j.run(ctx)
+}
+
+func (j *job) run(ctx context.Context) {
+	defer j.wg.Done()
+
+	for {
+		err := j.call(ctx)
+
+		j.mu.Lock()
+		j.runs++
+		if err != nil {
+			j.errors++
+			j.inARow++
+		} else {
+			j.inARow = 0
+		}
+		failed := j.cfg.failAfter > 0 && j.inARow >= j.cfg.failAfter
+		again := j.queued && ctx.Err() == nil
+		j.queued = false
+		if !again {
+			j.running--
+		}
+		j.mu.Unlock()
+
+		if err != nil {
+			j.cfg.onError(ctx, err)
+		}
+
+		if failed {
+			select {
+			case j.failed <- fmt.Errorf("%d runs failed in a row: %w", j.cfg.failAfter, err):
+			default:
+			}
+		}
+
+		if !again {
+			return
+		}
+	}
+}
+
+func (j *job) call(ctx context.Context) (err error) {
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = fmt.Errorf("panic: %v", panicErr)
+		}
+	}()
+
+	if j.cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithTimeout(ctx, clock.FromContext(ctx), j.cfg.timeout)
+		defer cancel()
+	}
+
+	return j.fn(ctx)
+}
+
+// close waits for the runs in flight. If ctx is done first, their context is cancelled.
+func (j *job) close(ctx context.Context) error {
+	j.mu.Lock()
+	j.closed = true
+	j.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		j.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		j.mu.Lock()
+		if j.cancelRun != nil {
+			j.cancelRun()
+		}
+		j.mu.Unlock()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,147 @@
+package anchor
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/pprof"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kyuff/anchor/internal/goroutines"
+)
+
+const (
+	labelAnchor    = "anchor"
+	labelComponent = "anchor.component"
+	labelPhase     = "anchor.phase"
+)
+
+// anchorPackage is the import path in the stack of goroutines of the Anchor.
+const anchorPackage = "github.com/kyuff/anchor"
+
+var anchorIDs atomic.Int64
+
+// Leak is one or more goroutines with the same stack, that were left
+// running after the Anchor closed.
+//
+// Goroutines are attributed to the Component by the pprof labels set when
+// the Anchor calls it. Goroutines started from those calls inherit the labels.
+// With WithUnattributedLeaks, goroutines without labels that were started while
+// the Anchor ran are reported as well. They have no Component or Phase.
+type Leak struct {
+	// Component is the name of the Component that started the goroutines.
+	// It is empty when the goroutines are unattributed.
+	Component string
+	// Phase is the lifecycle method that started the goroutines: Setup, Start, Probe or Close.
+	// It is empty when the goroutines are unattributed.
+	Phase string
+	// Count is the number of goroutines.
+	Count int
+	// Stack of the goroutines.
+	Stack string
+}
+
+func (l Leak) String() string {
+	if l.Component == "" {
+		return fmt.Sprintf("%d goroutine(s) started outside of a Component:\n%s", l.Count, l.Stack)
+	}
+
+	return fmt.Sprintf("%d goroutine(s) from %s of %q:\n%s", l.Count, l.Phase, l.Component, l.Stack)
+}
+
+// Leaks returns the goroutines started by Components that are still running.
+// See WithUnattributedLeaks to also report goroutines without labels.
+//
+// It is meant to be called after Run returns. It waits up to grace for the
+// goroutines to exit, before they are considered leaked.
+func (a *Anchor) Leaks(grace time.Duration) []Leak {
+	var (
+		deadline = time.Now().Add(grace)
+		leaks    = a.leaks()
+	)
+	for len(leaks) > 0 && time.Now().Before(deadline) {
+		time.Sleep(min(grace/20, time.Until(deadline)))
+		leaks = a.leaks()
+	}
+
+	return leaks
+}
+
+func (a *Anchor) leaks() []Leak {
+	var (
+		profile = goroutines.Profile()
+		leaks   []Leak
+	)
+	for _, g := range goroutines.Labeled(profile, labelAnchor, a.id) {
+		leaks = append(leaks, Leak{
+			Component: g.Labels[labelComponent],
+			Phase:     g.Labels[labelPhase],
+			Count:     g.Count,
+			Stack:     g.Stack,
+		})
+	}
+
+	a.mu.Lock()
+	snapshot := a.snapshot
+	a.mu.Unlock()
+	if snapshot == nil {
+		// not enabled, or Run was not called, so there is nothing to compare with
+		return leaks
+	}
+
+	for _, g := range goroutines.Leftover(snapshot, profile) {
+		if len(g.Labels) > 0 || strings.Contains(g.Stack, anchorPackage+".(*Anchor).") {
+			// labelled goroutines belong to other code, and those of the Anchor
+			// itself only wait for the Components
+			continue
+		}
+
+		leaks = append(leaks, Leak{
+			Count: g.Count,
+			Stack: g.Stack,
+		})
+	}
+
+	return leaks
+}
+
+// takeSnapshot of the goroutines of the process, to find those started while the Anchor runs.
+func (a *Anchor) takeSnapshot() {
+	if !a.cfg.unattributedLeaks {
+		return
+	}
+
+	snapshot := goroutines.Profile()
+	if snapshot == nil {
+		snapshot = []goroutines.Group{}
+	}
+
+	a.mu.Lock()
+	a.snapshot = snapshot
+	a.mu.Unlock()
+}
+
+func (a *Anchor) checkLeaks() {
+	if a.cfg.leakGrace <= 0 {
+		return
+	}
+
+	for _, leak := range a.Leaks(a.cfg.leakGrace) {
+		a.error(a.cfg.anchorCtx, "[anchor] Goroutine leak "+leak.String(),
+			slog.String("component", leak.Component),
+			phaseAttr(leak.Phase),
+			slog.Int("count", leak.Count),
+		)
+	}
+}
+
+// labeled calls fn with pprof labels that attribute goroutines to the Component.
+func (a *Anchor) labeled(ctx context.Context, component fullComponent, phase string, fn func(ctx context.Context)) {
+	pprof.Do(ctx, pprof.Labels(
+		labelAnchor, a.id,
+		labelComponent, component.Name(),
+		labelPhase, phase,
+	), fn)
+}
@@ -0,0 +1,172 @@
+package anchor_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+type leakyComponent struct {
+	release chan struct{}
+}
+
+func (c *leakyComponent) Name() string {
+	return "leaky"
+}
+
+func (c *leakyComponent) Start(ctx context.Context) error {
+	go func() {
+		<-c.release
+	}()
+
+	<-ctx.Done()
+	return nil
+}
+
+// spawnerComponent starts a goroutine in its constructor, that starts
+// another goroutine when the component is Started.
+type spawnerComponent struct {
+	spawn   chan struct{}
+	release chan struct{}
+}
+
+func newSpawnerComponent() *spawnerComponent {
+	c := &spawnerComponent{
+		spawn:   make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	go func() {
+		<-c.spawn
+		go func() {
+			<-c.release
+		}()
+	}()
+
+	return c
+}
+
+func (c *spawnerComponent) Name() string {
+	return "spawner"
+}
+
+func (c *spawnerComponent) Start(ctx context.Context) error {
+	close(c.spawn)
+	<-ctx.Done()
+	return nil
+}
+
+func TestLeaks(t *testing.T) {
+	var (
+		newWire = func() anchor.Wire {
+			return anchor.WireFunc(func(ctx context.Context) (context.Context, context.CancelFunc) {
+				return context.WithTimeout(ctx, 50*time.Millisecond)
+			})
+		}
+	)
+
+	t.Run("attribute leaks to component", func(t *testing.T) {
+		// arrange
+		var (
+			component = &leakyComponent{release: make(chan struct{})}
+			sut       = anchor.New(newWire()).Add(component)
+		)
+
+		t.Cleanup(func() { close(component.release) })
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		leaks := sut.Leaks(10 * time.Millisecond)
+		if assert.Equal(t, 1, len(leaks)) {
+			assert.Equal(t, "leaky", leaks[0].Component)
+			assert.Equal(t, "Start", leaks[0].Phase)
+			assert.Equal(t, 1, leaks[0].Count)
+		}
+	})
+
+	t.Run("report leaks started outside of components", func(t *testing.T) {
+		// arrange
+		var (
+			component = newSpawnerComponent()
+			sut       = anchor.New(newWire(), anchor.WithUnattributedLeaks()).Add(component)
+		)
+
+		t.Cleanup(func() { close(component.release) })
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		leaks := sut.Leaks(10 * time.Millisecond)
+		if assert.Equal(t, 1, len(leaks)) {
+			assert.Equal(t, "", leaks[0].Component)
+			assert.Equal(t, "", leaks[0].Phase)
+			assert.Equal(t, 1, leaks[0].Count)
+			assert.Truef(t, strings.HasPrefix(leaks[0].String(), "1 goroutine(s) started outside of a Component"), "wrong string: %s", leaks[0])
+		}
+	})
+
+	t.Run("ignore leaks started outside of components by default", func(t *testing.T) {
+		// arrange
+		var (
+			component = newSpawnerComponent()
+			sut       = anchor.New(newWire()).Add(component)
+		)
+
+		t.Cleanup(func() { close(component.release) })
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, 0, len(sut.Leaks(10*time.Millisecond)))
+	})
+
+	t.Run("no leaks when goroutines exit", func(t *testing.T) {
+		// arrange
+		var (
+			component = &leakyComponent{release: make(chan struct{})}
+			sut       = anchor.New(newWire()).Add(component)
+		)
+
+		// act
+		code := sut.Run()
+		close(component.release)
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, 0, len(sut.Leaks(time.Second)))
+	})
+
+	t.Run("log leaks after run", func(t *testing.T) {
+		// arrange
+		var (
+			buf       bytes.Buffer
+			component = &leakyComponent{release: make(chan struct{})}
+			sut       = anchor.New(newWire(),
+				anchor.WithSlog(slog.New(slog.NewTextHandler(&buf, nil))),
+				anchor.WithLeakCheck(10*time.Millisecond),
+			).Add(component)
+		)
+
+		t.Cleanup(func() { close(component.release) })
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Truef(t, strings.Contains(buf.String(), `Goroutine leak 1 goroutine(s) from Start of \"leaky\"`), "missing log: %s", buf.String())
+	})
+}
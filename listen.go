@@ -0,0 +1,70 @@
+package anchor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/kyuff/anchor/internal/activation"
+)
+
+// activated holds the listeners passed by systemd socket activation.
+// They are read once, as they are passed once to the process.
+var activated = &listenerRegistry{load: activation.Listeners}
+
+type listenerRegistry struct {
+	load func() (map[string][]net.Listener, error)
+
+	once      sync.Once
+	mu        sync.Mutex
+	listeners map[string][]net.Listener
+	err       error
+}
+
+// Listen returns a listener for the name. Call it in Setup, so all listeners are bound
+// before any Component Starts, and an address in use fails the Anchor with SetupFailed.
+//
+// When the process is started by systemd socket activation, a listener passed with
+// the name in LISTEN_FDNAMES is returned instead of binding the address.
+// Each passed listener is returned once.
+func Listen(ctx context.Context, name, network, address string) (net.Listener, error) {
+	listener, err := activated.take(name)
+	if err != nil {
+		return nil, fmt.Errorf("socket activation: %w", err)
+	}
+
+	if listener != nil {
+		return listener, nil
+	}
+
+	var lc net.ListenConfig
+	listener, err = lc.Listen(ctx, network, address)
+	if err != nil {
+		return nil, fmt.Errorf("listen %s on %s: %w", name, address, err)
+	}
+
+	return listener, nil
+}
+
+// take the next listener passed with the name. It is nil when there is none.
+func (r *listenerRegistry) take(name string) (net.Listener, error) {
+	r.once.Do(func() {
+		r.listeners, r.err = r.load()
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	named := r.listeners[name]
+	if len(named) == 0 {
+		return nil, nil
+	}
+
+	r.listeners[name] = named[1:]
+	return named[0], nil
+}
@@ -0,0 +1,77 @@
+package anchor_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestListen(t *testing.T) {
+	t.Run("bind the address", func(t *testing.T) {
+		// act
+		listener, err := anchor.Listen(t.Context(), "http", "tcp", "127.0.0.1:0")
+
+		// assert
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = listener.Close() })
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if assert.NoError(t, err) {
+			assert.NoError(t, conn.Close())
+		}
+	})
+
+	t.Run("fail when the address is in use", func(t *testing.T) {
+		// arrange
+		taken, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = taken.Close() })
+
+		// act
+		_, err = anchor.Listen(t.Context(), "http", "tcp", taken.Addr().String())
+
+		// assert
+		assert.Error(t, err)
+	})
+
+	t.Run("bind all before start", func(t *testing.T) {
+		// arrange
+		var (
+			listeners []net.Listener
+			listen    = func(name string) anchor.Component {
+				return anchor.Build(name).
+					Setup(func(ctx context.Context) error {
+						listener, err := anchor.Listen(ctx, name, "tcp", "127.0.0.1:0")
+						listeners = append(listeners, listener)
+						return err
+					}).
+					Start(func(ctx context.Context) error {
+						// every listener accepts connections before a Component starts
+						for _, listener := range listeners {
+							conn, err := net.Dial("tcp", listener.Addr().String())
+							if err != nil {
+								return err
+							}
+							_ = conn.Close()
+						}
+						return nil
+					}).
+					Component()
+			}
+		)
+		t.Cleanup(func() {
+			for _, listener := range listeners {
+				_ = listener.Close()
+			}
+		})
+
+		// act
+		code := anchor.New(anchor.ManualWire()).Add(listen("a"), listen("b")).Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, 2, len(listeners))
+	})
+}
@@ -1,12 +1,88 @@
 package anchor
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
 
 // Logger is an interface for logging by the Anchor.
 // There is default implementation for slog.Logger.
+//
+// A Logger that also implements StructuredLogger is used for structured logging.
 type Logger interface {
 	// InfofCtx logs on an INFO level
 	InfofCtx(ctx context.Context, template string, args ...any)
 	// ErrorfCtx logs on an ERROR level
 	ErrorfCtx(ctx context.Context, template string, args ...any)
 }
+
+// StructuredLogger logs a message with attributes on a level.
+//
+// The Anchor logs the attributes component, phase, duration, attempt and error.
+// A *slog.Logger implements it.
+type StructuredLogger interface {
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// printfLogger adapts a Logger to a StructuredLogger by formatting the attributes
+// after the message. Debug messages are not logged, as a Logger has no such level.
+type printfLogger struct {
+	logger Logger
+}
+
+func (l printfLogger) LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if level < slog.LevelInfo {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, attr := range attrs {
+		_, _ = fmt.Fprintf(&b, " %s=%v", attr.Key, attr.Value)
+	}
+
+	if level < slog.LevelWarn {
+		l.logger.InfofCtx(ctx, "%s", b.String())
+	} else {
+		l.logger.ErrorfCtx(ctx, "%s", b.String())
+	}
+}
+
+func (a *Anchor) debug(ctx context.Context, msg string, attrs ...slog.Attr) {
+	a.cfg.logger.LogAttrs(ctx, slog.LevelDebug, msg, attrs...)
+}
+
+func (a *Anchor) info(ctx context.Context, msg string, attrs ...slog.Attr) {
+	a.cfg.logger.LogAttrs(ctx, slog.LevelInfo, msg, attrs...)
+}
+
+func (a *Anchor) warn(ctx context.Context, msg string, attrs ...slog.Attr) {
+	a.cfg.logger.LogAttrs(ctx, slog.LevelWarn, msg, attrs...)
+}
+
+func (a *Anchor) error(ctx context.Context, msg string, attrs ...slog.Attr) {
+	a.cfg.logger.LogAttrs(ctx, slog.LevelError, msg, attrs...)
+}
+
+func componentAttr(component fullComponent) slog.Attr {
+	return slog.String("component", component.Name())
+}
+
+func phaseAttr(phase string) slog.Attr {
+	return slog.String("phase", phase)
+}
+
+func durationAttr(d time.Duration) slog.Attr {
+	return slog.Duration("duration", d)
+}
+
+func attemptAttr(attempt int) slog.Attr {
+	return slog.Int("attempt", attempt)
+}
+
+func errorAttr(err any) slog.Attr {
+	return slog.Any("error", err)
+}
@@ -0,0 +1,138 @@
+package anchor_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+// printfLogger only implements the Logger interface.
+type printfLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *printfLogger) InfofCtx(_ context.Context, template string, args ...any) {
+	l.log("INFO "+template, args...)
+}
+
+func (l *printfLogger) ErrorfCtx(_ context.Context, template string, args ...any) {
+	l.log("ERROR "+template, args...)
+}
+
+func (l *printfLogger) log(template string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(template, args...))
+}
+
+func (l *printfLogger) snapshot() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+func (l *printfLogger) contains(line string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, got := range l.lines {
+		if strings.HasPrefix(got, line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestLogger(t *testing.T) {
+	var (
+		runAnchor = func(opt anchor.Option, component anchor.Component) int {
+			wire := anchor.ManualWire()
+			return anchor.New(wire,
+				opt,
+				anchor.WithReadyCallback(func(ctx context.Context) error {
+					wire.Stop(nil)
+					return nil
+				}),
+			).Add(component).Run()
+		}
+	)
+
+	t.Run("log attributes to slog", func(t *testing.T) {
+		// arrange
+		var (
+			buf syncBuffer
+			log = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		)
+
+		// act
+		code := runAnchor(anchor.WithSlog(log), &tracedComponent{name: "db", setupErr: errors.New("boom")})
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+		var found bool
+		for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &record))
+			if record["msg"] == "[anchor] Setup failed" {
+				found = true
+				assert.Equal[any](t, "ERROR", record["level"])
+				assert.Equal[any](t, "db", record["component"])
+				assert.Equal[any](t, "Setup", record["phase"])
+				assert.Equal[any](t, "boom", record["error"])
+				assert.NotNil(t, record["duration"])
+			}
+		}
+		assert.Truef(t, found, "missing record in: %s", buf.String())
+	})
+
+	t.Run("log probe attempts on debug", func(t *testing.T) {
+		// arrange
+		var (
+			buf syncBuffer
+			log = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		)
+
+		// act
+		runAnchor(anchor.WithSlog(log), &tracedComponent{name: "db", probes: 2})
+
+		// assert
+		assert.Truef(t, strings.Contains(buf.String(), `level=DEBUG msg="[anchor] Probe failed" component=db phase=Probe attempt=1`), "log: %s", buf.String())
+	})
+
+	t.Run("format attributes for a Logger", func(t *testing.T) {
+		// arrange
+		var (
+			log = &printfLogger{}
+		)
+
+		// act
+		code := runAnchor(anchor.WithLogger(log), &tracedComponent{name: "db", setupErr: errors.New("boom")})
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+		assert.Truef(t, log.contains("ERROR [anchor] Setup failed component=db phase=Setup duration="), "lines: %v", log.snapshot())
+		assert.Truef(t, log.contains("INFO [anchor] Closed component component=db phase=Close"), "lines: %v", log.snapshot())
+	})
+
+	t.Run("leave out debug for a Logger", func(t *testing.T) {
+		// arrange
+		var (
+			log = &printfLogger{}
+		)
+
+		// act
+		runAnchor(anchor.WithLogger(log), &tracedComponent{name: "db", probes: 2})
+
+		// assert
+		assert.Falsef(t, log.contains("INFO [anchor] Probe failed"), "lines: %v", log.snapshot())
+	})
+}
@@ -0,0 +1,120 @@
+package anchor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrStopped is the cause of a shutdown by [Manual.Stop] when no cause is given.
+var ErrStopped = errors.New("anchor stopped")
+
+// SignalError is the cause of a shutdown by a signal simulated with [Manual.Signal].
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (err SignalError) Error() string {
+	return fmt.Sprintf("received signal %s", err.Signal)
+}
+
+// lifecycleWire is a Wire that is told when the Anchor is ready and done.
+type lifecycleWire interface {
+	Wire
+	ready()
+	done(code int)
+}
+
+// Manual is a Wire controlled by the caller, primarily for tests.
+//
+// It allows a test to start the Anchor, wait until it is Ready, make assertions
+// and then Stop it with a cause.
+type Manual struct {
+	mu     sync.Mutex
+	cancel context.CancelCauseFunc
+	cause  error
+
+	readyOnce sync.Once
+	readyChan chan struct{}
+	doneChan  chan int
+}
+
+// ManualWire returns a Wire that runs until Stop or Signal is called.
+// It can be used by a single Anchor.
+func ManualWire() *Manual {
+	return &Manual{
+		readyChan: make(chan struct{}),
+		doneChan:  make(chan int, 1),
+	}
+}
+
+// Wire the Anchor to the Manual controls.
+func (m *Manual) Wire(ctx context.Context) (context.Context, context.CancelFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	m.cancel = cancel
+	if m.cause != nil {
+		cancel(m.cause)
+	}
+
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// Stop the Anchor with the cause. It is safe to call before the Anchor runs
+// and more than once, in which case the first cause is kept.
+func (m *Manual) Stop(cause error) {
+	if cause == nil {
+		cause = ErrStopped
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cause == nil {
+		m.cause = cause
+	}
+
+	if m.cancel != nil {
+		m.cancel(m.cause)
+	}
+}
+
+// Signal simulates that the application received the signal, like [SignalWire] does.
+func (m *Manual) Signal(sig os.Signal) {
+	m.Stop(SignalError{Signal: sig})
+}
+
+// Cause returns the cause given to Stop or Signal.
+func (m *Manual) Cause() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.cause
+}
+
+// Ready returns a channel that is closed when all Components are ready.
+func (m *Manual) Ready() <-chan struct{} {
+	return m.readyChan
+}
+
+// Done returns a channel that receives the exit code once Run returns.
+func (m *Manual) Done() <-chan int {
+	return m.doneChan
+}
+
+func (m *Manual) ready() {
+	m.readyOnce.Do(func() {
+		close(m.readyChan)
+	})
+}
+
+func (m *Manual) done(code int) {
+	select {
+	case m.doneChan <- code:
+	default:
+	}
+}
@@ -0,0 +1,120 @@
+package anchor_test
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestManualWire(t *testing.T) {
+	var (
+		newComponent = func() *fullComponentMock {
+			return &fullComponentMock{
+				CloseFunc: func(ctx context.Context) error { return nil },
+				NameFunc:  func() string { return "component" },
+				SetupFunc: func(ctx context.Context) error { return nil },
+				StartFunc: func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				},
+				ProbeFunc: func(ctx context.Context) error { return nil },
+			}
+		}
+	)
+
+	t.Run("stop with cause when ready", func(t *testing.T) {
+		// arrange
+		var (
+			sut       = anchor.ManualWire()
+			component = newComponent()
+			cause     = errors.New("test done")
+		)
+
+		go anchor.New(sut).Add(component).Run()
+
+		// act
+		<-sut.Ready()
+		assert.Equal(t, 1, len(component.StartCalls()))
+		assert.Equal(t, 0, len(component.CloseCalls()))
+		sut.Stop(cause)
+
+		// assert
+		assert.Equal(t, anchor.OK, <-sut.Done())
+		assert.Equal(t, 1, len(component.CloseCalls()))
+		assert.Equal(t, cause, sut.Cause())
+	})
+
+	t.Run("stop by simulated signal", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.ManualWire()
+		)
+
+		go anchor.New(sut).Add(newComponent()).Run()
+		<-sut.Ready()
+
+		// act
+		sut.Signal(syscall.SIGTERM)
+
+		// assert
+		assert.Equal(t, anchor.OK, <-sut.Done())
+		var signalErr anchor.SignalError
+		assert.Truef(t, errors.As(sut.Cause(), &signalErr), "cause is not a signal: %v", sut.Cause())
+		assert.Equal(t, "received signal terminated", sut.Cause().Error())
+	})
+
+	t.Run("stop before run", func(t *testing.T) {
+		// arrange
+		var (
+			sut       = anchor.ManualWire()
+			component = newComponent()
+		)
+
+		sut.Stop(nil)
+
+		// act
+		code := anchor.New(sut).Add(component).Run()
+
+		// assert
+		assert.Equal(t, code, <-sut.Done())
+		assert.Equal(t, anchor.ErrStopped, sut.Cause())
+		assert.Equal(t, 0, len(component.StartCalls()))
+	})
+
+	t.Run("done with failure code", func(t *testing.T) {
+		// arrange
+		var (
+			sut       = anchor.ManualWire()
+			component = newComponent()
+		)
+
+		component.SetupFunc = func(ctx context.Context) error {
+			return errors.New("FAIL")
+		}
+
+		// act
+		go anchor.New(sut).Add(component).Run()
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, <-sut.Done())
+	})
+
+	t.Run("keep first cause", func(t *testing.T) {
+		// arrange
+		var (
+			sut   = anchor.ManualWire()
+			first = errors.New("first")
+		)
+
+		// act
+		sut.Stop(first)
+		sut.Stop(errors.New("second"))
+
+		// assert
+		assert.Equal(t, first, sut.Cause())
+	})
+}
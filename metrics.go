@@ -0,0 +1,314 @@
+package anchor
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// States of a Component in the anchor_component_state metric.
+// A Component that failed stays failed when it is closed.
+var metricStates = []string{"pending", "setup", "set_up", "starting", "ready", "stopped", "closing", "closed", "failed"}
+
+// metricBuckets are the upper bounds in seconds of the duration histograms.
+var metricBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Metrics of the lifecycle of an Anchor in the Prometheus text exposition format.
+//
+// Use WithMetrics to record an Anchor. The same Metrics can be given to each Anchor
+// run by a process, so a Component that is started again is counted as a restart.
+//
+// The series of a Component have the labels component and index, which is the position
+// it was added to the Anchor in, so Components with the same name are kept apart.
+//
+// The metrics are:
+//
+//	anchor_component_state             gauge with the current state of each Component
+//	anchor_component_setup_seconds     histogram of the Setup duration
+//	anchor_component_close_seconds     histogram of the Close duration
+//	anchor_component_probes_total      counter of Probe attempts by result
+//	anchor_component_restarts_total    counter of Runs after the first in which the Component started
+//	anchor_time_to_ready_seconds       gauge with the time from Run until ready
+//	anchor_shutdown_cause              gauge with the cause of the last shutdown
+type Metrics struct {
+	mu         sync.Mutex
+	components map[componentKey]*componentMetrics
+	keys       []componentKey
+	runs       int
+	runAt      time.Time
+	readyIn    time.Duration
+	isReady    bool
+	cause      string
+}
+
+// componentKey identifies a Component by its name and position in the Anchor.
+type componentKey struct {
+	index int
+	name  string
+}
+
+func (k componentKey) labels(pairs ...string) string {
+	return labels(append([]string{"component", k.name, "index", strconv.Itoa(k.index)}, pairs...)...)
+}
+
+type componentMetrics struct {
+	state    string
+	began    map[string]time.Time
+	setup    histogram
+	close    histogram
+	probesOK int
+	probesKO int
+	// startedIn is the number of the Run in which the Component was last started
+	startedIn int
+	restarts  int
+}
+
+type histogram struct {
+	counts []int
+	sum    float64
+	count  int
+}
+
+func (h *histogram) observe(d time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]int, len(metricBuckets))
+	}
+
+	seconds := d.Seconds()
+	for i, bound := range metricBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// NewMetrics creates empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		components: make(map[componentKey]*componentMetrics),
+	}
+}
+
+func (m *Metrics) component(index int, name string) *componentMetrics {
+	key := componentKey{index: index, name: name}
+	c, ok := m.components[key]
+	if !ok {
+		c = &componentMetrics{began: make(map[string]time.Time)}
+		m.components[key] = c
+		m.keys = append(m.keys, key)
+		slices.SortFunc(m.keys, func(a, b componentKey) int {
+			return cmp.Or(cmp.Compare(a.index, b.index), strings.Compare(a.name, b.name))
+		})
+	}
+
+	return c
+}
+
+func (m *Metrics) run(components []string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runs++
+	m.runAt = at
+	m.isReady = false
+	m.cause = ""
+	for index, name := range components {
+		m.component(index, name).state = "pending"
+	}
+}
+
+func (m *Metrics) begin(index int, component, phase string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.component(index, component)
+	c.began[phase] = at
+	switch phase {
+	case "Setup":
+		c.state = "setup"
+	case "Start":
+		if c.startedIn != 0 && c.startedIn != m.runs {
+			c.restarts++
+		}
+		c.startedIn = m.runs
+		c.state = "starting"
+	case "Close":
+		if c.state != "failed" {
+			c.state = "closing"
+		}
+	}
+}
+
+func (m *Metrics) end(index int, component, phase string, at time.Time, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c := m.component(index, component)
+	d := at.Sub(c.began[phase])
+	switch phase {
+	case "Setup":
+		c.setup.observe(d)
+		c.state = "set_up"
+	case "Start":
+		if c.state == "starting" || c.state == "ready" {
+			c.state = "stopped"
+		}
+		if errors.Is(err, context.Canceled) {
+			// the Component exits as the Anchor shuts down
+			return
+		}
+	case "Probe":
+		if err != nil {
+			c.probesKO++
+			return
+		}
+		c.probesOK++
+		if c.state == "starting" {
+			c.state = "ready"
+		}
+	case "Close":
+		c.close.observe(d)
+		if c.state != "failed" {
+			c.state = "closed"
+		}
+	}
+
+	if err != nil {
+		c.state = "failed"
+	}
+}
+
+func (m *Metrics) ready(at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.readyIn = at.Sub(m.runAt)
+	m.isReady = true
+}
+
+func (m *Metrics) shutdown(cause string, _ time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.cause = cause
+}
+
+func (m *Metrics) done(_ int, _ time.Time) {}
+
+// WriteTo writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	var b strings.Builder
+	m.write(&b)
+	m.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// ServeHTTP responds with the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = m.WriteTo(w)
+}
+
+func (m *Metrics) write(b *strings.Builder) {
+	header(b, "anchor_component_state", "gauge", "Current state of the Component.")
+	for _, key := range m.keys {
+		for _, state := range metricStates {
+			value := 0
+			if m.components[key].state == state {
+				value = 1
+			}
+			sample(b, "anchor_component_state", key.labels("state", state), strconv.Itoa(value))
+		}
+	}
+
+	header(b, "anchor_component_setup_seconds", "histogram", "Duration of the Component Setup.")
+	for _, key := range m.keys {
+		m.components[key].setup.write(b, "anchor_component_setup_seconds", key)
+	}
+
+	header(b, "anchor_component_close_seconds", "histogram", "Duration of the Component Close.")
+	for _, key := range m.keys {
+		m.components[key].close.write(b, "anchor_component_close_seconds", key)
+	}
+
+	header(b, "anchor_component_probes_total", "counter", "Probe attempts of the Component by result.")
+	for _, key := range m.keys {
+		c := m.components[key]
+		sample(b, "anchor_component_probes_total", key.labels("result", "success"), strconv.Itoa(c.probesOK))
+		sample(b, "anchor_component_probes_total", key.labels("result", "failure"), strconv.Itoa(c.probesKO))
+	}
+
+	header(b, "anchor_component_restarts_total", "counter", "Runs after the first in which the Component started.")
+	for _, key := range m.keys {
+		sample(b, "anchor_component_restarts_total", key.labels(), strconv.Itoa(m.components[key].restarts))
+	}
+
+	if m.isReady {
+		header(b, "anchor_time_to_ready_seconds", "gauge", "Time from Run until the Anchor was ready.")
+		sample(b, "anchor_time_to_ready_seconds", "", formatFloat(m.readyIn.Seconds()))
+	}
+
+	if m.cause != "" {
+		header(b, "anchor_shutdown_cause", "gauge", "Cause of the last shutdown of the Anchor.")
+		sample(b, "anchor_shutdown_cause", labels("cause", m.cause), "1")
+	}
+}
+
+func (h histogram) write(b *strings.Builder, metric string, key componentKey) {
+	for i, bound := range metricBuckets {
+		count := 0
+		if h.counts != nil {
+			count = h.counts[i]
+		}
+		sample(b, metric+"_bucket", key.labels("le", formatFloat(bound)), strconv.Itoa(count))
+	}
+	sample(b, metric+"_bucket", key.labels("le", "+Inf"), strconv.Itoa(h.count))
+	sample(b, metric+"_sum", key.labels(), formatFloat(h.sum))
+	sample(b, metric+"_count", key.labels(), strconv.Itoa(h.count))
+}
+
+func header(b *strings.Builder, metric, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", metric, help, metric, kind)
+}
+
+func sample(b *strings.Builder, metric, labels, value string) {
+	fmt.Fprintf(b, "%s%s %s\n", metric, labels, value)
+}
+
+// labels formats pairs of label names and values.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `%s="%s"`, pairs[i], escapeLabel(pairs[i+1]))
+	}
+	b.WriteString("}")
+
+	return b.String()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
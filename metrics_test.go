@@ -0,0 +1,191 @@
+package anchor_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	var (
+		runAnchor = func(metrics *anchor.Metrics, components ...anchor.Component) int {
+			wire := anchor.ManualWire()
+			return anchor.New(wire,
+				anchor.WithMetrics(metrics),
+				anchor.WithReadyCallback(func(ctx context.Context) error {
+					wire.Stop(nil)
+					return nil
+				}),
+			).Add(components...).Run()
+		}
+		export = func(t *testing.T, metrics *anchor.Metrics) string {
+			t.Helper()
+			var b strings.Builder
+			_, err := metrics.WriteTo(&b)
+			assert.NoError(t, err)
+			return b.String()
+		}
+		assertContains = func(t *testing.T, got string, lines ...string) {
+			t.Helper()
+			for _, line := range lines {
+				assert.Truef(t, strings.Contains(got, line+"\n"), "missing %q in:\n%s", line, got)
+			}
+		}
+	)
+
+	t.Run("record the lifecycle", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+		)
+
+		// act
+		code := runAnchor(metrics, &tracedComponent{name: "db"})
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		got := export(t, metrics)
+		assertContains(t, got,
+			"# TYPE anchor_component_state gauge",
+			`anchor_component_state{component="db",index="0",state="closed"} 1`,
+			`anchor_component_state{component="db",index="0",state="ready"} 0`,
+			"# TYPE anchor_component_setup_seconds histogram",
+			`anchor_component_setup_seconds_bucket{component="db",index="0",le="+Inf"} 1`,
+			`anchor_component_setup_seconds_count{component="db",index="0"} 1`,
+			`anchor_component_close_seconds_count{component="db",index="0"} 1`,
+			`anchor_component_probes_total{component="db",index="0",result="success"} 1`,
+			`anchor_component_restarts_total{component="db",index="0"} 0`,
+			"# TYPE anchor_time_to_ready_seconds gauge",
+			`anchor_shutdown_cause{cause="context"} 1`,
+		)
+	})
+
+	t.Run("count restarts", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+		)
+
+		// act
+		runAnchor(metrics, &tracedComponent{name: "db"})
+		runAnchor(metrics, &tracedComponent{name: "db"})
+
+		// assert
+		assertContains(t, export(t, metrics),
+			`anchor_component_restarts_total{component="db",index="0"} 1`,
+			`anchor_component_setup_seconds_count{component="db",index="0"} 2`,
+		)
+	})
+
+	t.Run("count no restarts for components with the same name", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+		)
+
+		// act
+		runAnchor(metrics, &tracedComponent{name: "db"}, &tracedComponent{name: "db"})
+
+		// assert
+		assertContains(t, export(t, metrics),
+			`anchor_component_restarts_total{component="db",index="0"} 0`,
+			`anchor_component_restarts_total{component="db",index="1"} 0`,
+		)
+	})
+
+	t.Run("keep components with the same name apart", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+		)
+
+		// act
+		runAnchor(metrics, &tracedComponent{name: "db"}, &tracedComponent{name: "db"})
+
+		// assert
+		assertContains(t, export(t, metrics),
+			`anchor_component_state{component="db",index="0",state="closed"} 1`,
+			`anchor_component_state{component="db",index="1",state="closed"} 1`,
+			`anchor_component_setup_seconds_count{component="db",index="0"} 1`,
+			`anchor_component_setup_seconds_count{component="db",index="1"} 1`,
+			`anchor_component_probes_total{component="db",index="0",result="success"} 1`,
+			`anchor_component_probes_total{component="db",index="1",result="success"} 1`,
+		)
+	})
+
+	t.Run("record start canceled on shutdown as closed", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+		)
+
+		// act
+		code := runAnchor(metrics, anchor.Func("worker", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assertContains(t, export(t, metrics),
+			`anchor_component_state{component="worker",index="0",state="closed"} 1`,
+			`anchor_component_state{component="worker",index="0",state="failed"} 0`,
+		)
+	})
+
+	t.Run("record failed setup", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+		)
+
+		// act
+		code := runAnchor(metrics, &tracedComponent{name: "db", setupErr: errors.New("fail")})
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+		got := export(t, metrics)
+		assertContains(t, got,
+			`anchor_component_state{component="db",index="0",state="failed"} 1`,
+			`anchor_shutdown_cause{cause="setup_failed"} 1`,
+		)
+		assert.Falsef(t, strings.Contains(got, "anchor_time_to_ready_seconds"), "not ready:\n%s", got)
+	})
+
+	t.Run("escape label values", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+		)
+
+		// act
+		runAnchor(metrics, &tracedComponent{name: "a \"quoted\"\\name"})
+
+		// assert
+		assertContains(t, export(t, metrics),
+			`anchor_component_restarts_total{component="a \"quoted\"\\name",index="0"} 0`,
+		)
+	})
+
+	t.Run("serve over http", func(t *testing.T) {
+		// arrange
+		var (
+			metrics = anchor.NewMetrics()
+			rec     = httptest.NewRecorder()
+		)
+
+		runAnchor(metrics, &tracedComponent{name: "db"})
+
+		// act
+		metrics.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+		// assert
+		assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+		assertContains(t, rec.Body.String(), `anchor_component_state{component="db",index="0",state="closed"} 1`)
+	})
+}
@@ -0,0 +1,47 @@
+package anchor
+
+import "time"
+
+// Causes of an Anchor shutting down.
+const (
+	causeContext     = "context"
+	causeShutdown    = "shutdown"
+	causeCompleted   = "completed"
+	causeSetupFailed = "setup_failed"
+	causeError       = "error"
+)
+
+// observer is notified of the lifecycle of an Anchor and its Components.
+type observer interface {
+	// run is called when the Anchor is about to Setup the components.
+	run(components []string, at time.Time)
+	// begin is called when a Component enters a phase.
+	// The index is the position of the Component in the Anchor, as names can be shared.
+	begin(index int, component, phase string, at time.Time)
+	// end is called when a Component leaves a phase. The err is nil when it succeeded.
+	end(index int, component, phase string, at time.Time, err error)
+	// ready is called when the Anchor is ready.
+	ready(at time.Time)
+	// shutdown is called when the Anchor begins to close.
+	shutdown(cause string, at time.Time)
+	// done is called when Run is about to return the code.
+	done(code int, at time.Time)
+}
+
+func (a *Anchor) observe(fn func(o observer)) {
+	for _, o := range a.cfg.observers {
+		fn(o)
+	}
+}
+
+// shutdownCause of an Anchor that closes with the code.
+func shutdownCause(code int) string {
+	switch code {
+	case OK:
+		return causeCompleted
+	case SetupFailed:
+		return causeSetupFailed
+	default:
+		return causeError
+	}
+}
@@ -16,7 +16,21 @@ type Option func(cfg *config)
 // WithLogger sets the Logger for the application.
 //
 // Default: No logging is done.
+//
+// A Logger that also implements StructuredLogger is used for structured logging.
+// Otherwise the attributes are formatted after the message, and Debug messages are left out.
 func WithLogger(logger Logger) Option {
+	if structured, ok := logger.(StructuredLogger); ok {
+		return WithStructuredLogger(structured)
+	}
+
+	return WithStructuredLogger(printfLogger{logger: logger})
+}
+
+// WithStructuredLogger sets the StructuredLogger for the application.
+//
+// Default: No logging is done.
+func WithStructuredLogger(logger StructuredLogger) Option {
 	return func(opt *config) {
 		opt.logger = logger
 	}
@@ -34,14 +48,35 @@ func WithDefaultSlog() Option {
 
 // WithSlog uses the give *slog.Logger
 func WithSlog(log *slog.Logger) Option {
-	return WithLogger(
+	return WithStructuredLogger(
 		logger.NewSlog(log),
 	)
 }
 
+// WithTracer traces the lifecycle of the Anchor and its Components.
+//
+// Default: No tracing is done.
+func WithTracer(tracer Tracer) Option {
+	return func(cfg *config) {
+		cfg.tracer = tracer
+	}
+}
+
+// WithClock sets the Clock used to measure timeouts and ready check backoff.
+//
+// Default: the system clock
+func WithClock(c Clock) Option {
+	return func(cfg *config) {
+		cfg.clock = c
+	}
+}
+
 // WithAnchorContext runs the Anchor in the given Context. If it is
 // canceled, the Anchor will shutdown.
 //
+// The contexts given to Components are derived from it, so values like an
+// env.Scope from env.NewContext are passed on to them.
+//
 // Default: context.Background()
 func WithAnchorContext(ctx context.Context) Option {
 	return func(cfg *config) {
@@ -95,28 +130,78 @@ func WithReadyCallback(fn func(ctx context.Context) error) Option {
 //
 // Default: linear backoff with 100 millisecond increment
 func WithReadyCheckBackoff(fn func(ctx context.Context, attempt int) (time.Duration, error)) Option {
-	return func(cfg *config) {
-		cfg.readyCheckBackoff = fn
-	}
+	return withReadyCheckBackoff("custom", fn)
 }
 
 // WithFixedReadyCheckBackoff waits a fixed amount of time between retries.
 func WithFixedReadyCheckBackoff(d time.Duration) Option {
-	return WithReadyCheckBackoff(func(_ context.Context, _ int) (time.Duration, error) {
+	return withReadyCheckBackoff("fixed "+d.String(), func(_ context.Context, _ int) (time.Duration, error) {
 		return d, nil
 	})
 }
 
 // WithLinearReadyCheckBackoff increases the wait time linearly with each retry.
 func WithLinearReadyCheckBackoff(increment time.Duration) Option {
-	return WithReadyCheckBackoff(func(_ context.Context, retries int) (time.Duration, error) {
+	return withReadyCheckBackoff("linear "+increment.String(), func(_ context.Context, retries int) (time.Duration, error) {
 		return increment * time.Duration(retries), nil
 	})
 }
 
 // WithExponentialReadyCheckBackoff doubles the wait time with each retry.
 func WithExponentialReadyCheckBackoff(base time.Duration) Option {
-	return WithReadyCheckBackoff(func(_ context.Context, retries int) (time.Duration, error) {
+	return withReadyCheckBackoff("exponential "+base.String(), func(_ context.Context, retries int) (time.Duration, error) {
 		return base * time.Duration(1<<retries), nil
 	})
 }
+
+// withReadyCheckBackoff sets the backoff with a description that is shown by AdminHandler.
+func withReadyCheckBackoff(description string, fn func(ctx context.Context, attempt int) (time.Duration, error)) Option {
+	return func(cfg *config) {
+		cfg.readyCheckBackoff = fn
+		cfg.readyCheckBackoffDescription = description
+	}
+}
+
+// WithLeakCheck logs an error for each Leak of goroutines from Components,
+// that are still running grace after the Anchor closed.
+//
+// Default: No check
+func WithLeakCheck(grace time.Duration) Option {
+	return func(cfg *config) {
+		cfg.leakGrace = grace
+	}
+}
+
+// WithUnattributedLeaks makes Leaks report goroutines without pprof labels, that were started
+// while the Anchor ran and are still running. They are found by comparing with a snapshot
+// of the goroutines of the process, taken when Run starts. This finds goroutines that a
+// Component starts outside the calls from the Anchor, e.g. from a goroutine made in its constructor.
+//
+// As the snapshot covers the whole process, goroutines started by other code while the
+// Anchor ran are reported too. Do not use it with tests that run in parallel.
+//
+// Default: only goroutines started by the calls to Components are reported
+func WithUnattributedLeaks() Option {
+	return func(cfg *config) {
+		cfg.unattributedLeaks = true
+	}
+}
+
+// WithMetrics records the lifecycle of the Anchor in the Metrics.
+//
+// Default: No metrics are recorded.
+func WithMetrics(m *Metrics) Option {
+	return func(cfg *config) {
+		cfg.observers = append(cfg.observers, m)
+	}
+}
+
+// WithTimelineReport logs the Timeline when the Anchor is ready and when it has shut down,
+// both as a table and as a Gantt chart that is width characters wide.
+//
+// Default: No report
+func WithTimelineReport(width int) Option {
+	return func(cfg *config) {
+		cfg.timelineWidth = width
+	}
+}
@@ -53,6 +53,15 @@ func TestOptions(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:   "WithStructuredLogger",
+			option: WithStructuredLogger(slog.Default()),
+			assert: func(t *testing.T, cfg *config) {
+				if cfg.logger != slog.Default() {
+					t.Error("expected structured logger")
+				}
+			},
+		},
 		{
 			name:   "WithAnchorContext",
 			option: WithAnchorContext(context.Background()),
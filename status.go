@@ -0,0 +1,85 @@
+package anchor
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Phase of a Component in its lifecycle.
+type Phase string
+
+const (
+	// PhasePending is a Component that is not Setup yet.
+	PhasePending Phase = "pending"
+	// PhaseSettingUp is a Component in Setup.
+	PhaseSettingUp Phase = "setting-up"
+	// PhaseSetUp is a Component that completed Setup.
+	PhaseSetUp Phase = "set-up"
+	// PhaseStarting is a Component that is started, but has not succeeded a Probe yet.
+	PhaseStarting Phase = "starting"
+	// PhaseReady is a Component that succeeded a Probe.
+	PhaseReady Phase = "ready"
+	// PhaseRunning is a Component in an Anchor where all Components are ready.
+	PhaseRunning Phase = "running"
+	// PhaseClosing is a Component in Close.
+	PhaseClosing Phase = "closing"
+	// PhaseClosed is a Component that completed Close.
+	PhaseClosed Phase = "closed"
+	// PhaseFailed is a Component that returned an error or panicked. It stays failed when closed.
+	PhaseFailed Phase = "failed"
+)
+
+// ComponentStatus is a snapshot of a Component.
+type ComponentStatus struct {
+	ComponentTimeline
+	// Phase the Component is in.
+	Phase Phase
+	// Changed is when the Component entered the Phase.
+	Changed time.Time
+	// ProbeError is the error of the last Probe. It is nil when the Probe succeeded.
+	ProbeError error
+	// StartError is the error Start returned with. It is nil until Start returns.
+	StartError error
+}
+
+// Status returns a snapshot of each Component in the order they were added.
+//
+// It is safe to call from any goroutine, also while the Anchor runs.
+func (a *Anchor) Status() []ComponentStatus {
+	return a.tracker.snapshot()
+}
+
+func (s *ComponentStatus) enter(phase Phase, at time.Time) {
+	if s.Phase == PhaseFailed || s.Phase == phase {
+		return
+	}
+
+	s.Phase = phase
+	s.Changed = at
+}
+
+// MarshalJSON encodes the errors by their message.
+func (s ComponentStatus) MarshalJSON() ([]byte, error) {
+	type timeline ComponentTimeline
+	return json.Marshal(struct {
+		timeline
+		Phase      Phase     `json:"phase"`
+		Changed    time.Time `json:"changed,omitzero"`
+		ProbeError string    `json:"probe_error,omitempty"`
+		StartError string    `json:"start_error,omitempty"`
+	}{
+		timeline:   timeline(s.ComponentTimeline),
+		Phase:      s.Phase,
+		Changed:    s.Changed,
+		ProbeError: errorString(s.ProbeError),
+		StartError: errorString(s.StartError),
+	})
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
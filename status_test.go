@@ -0,0 +1,210 @@
+package anchor_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+type startErrComponent struct {
+	err error
+}
+
+func (c startErrComponent) Name() string {
+	return "worker"
+}
+
+func (c startErrComponent) Start(ctx context.Context) error {
+	return c.err
+}
+
+func TestStatus(t *testing.T) {
+	var (
+		phases = func(status []anchor.ComponentStatus) []anchor.Phase {
+			var got []anchor.Phase
+			for _, s := range status {
+				got = append(got, s.Phase)
+			}
+			return got
+		}
+	)
+
+	t.Run("pending before run", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.ManualWire()).Add(
+				&tracedComponent{name: "db"},
+				&tracedComponent{name: "http"},
+			)
+		)
+
+		// act
+		got := sut.Status()
+
+		// assert
+		if assert.Equal(t, 2, len(got)) {
+			assert.Equal(t, "db", got[0].Name)
+			assert.Equal(t, "http", got[1].Name)
+		}
+		assert.EqualSlice(t, []anchor.Phase{anchor.PhasePending, anchor.PhasePending}, phases(got))
+	})
+
+	t.Run("keep components with the same name apart", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.ManualWire()).Add(
+				&tracedComponent{name: "db", setupErr: errors.New("boom")},
+				&tracedComponent{name: "db"},
+			)
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		got := sut.Status()
+		if assert.Equal(t, 2, len(got)) {
+			assert.Equal(t, "db", got[0].Name)
+			assert.Equal(t, "db", got[1].Name)
+		}
+		assert.EqualSlice(t, []anchor.Phase{anchor.PhaseFailed, anchor.PhasePending}, phases(got))
+		assert.Equal(t, 2, len(sut.Timeline().Components))
+	})
+
+	t.Run("running when ready and closed after run", func(t *testing.T) {
+		// arrange
+		var (
+			wire    = anchor.ManualWire()
+			running []anchor.ComponentStatus
+			sut     = anchor.New(wire).Add(&tracedComponent{name: "db"})
+		)
+
+		go func() {
+			<-sut.Ready()
+			running = sut.Status()
+			wire.Stop(nil)
+		}()
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.EqualSlice(t, []anchor.Phase{anchor.PhaseRunning}, phases(running))
+		assert.Truef(t, !running[0].StartBegin.IsZero() && !running[0].Ready.IsZero(), "status: %+v", running[0])
+		assert.NoError(t, running[0].ProbeError)
+		got := sut.Status()
+		assert.EqualSlice(t, []anchor.Phase{anchor.PhaseClosed}, phases(got))
+		assert.Truef(t, !got[0].Changed.Before(got[0].CloseEnd), "status: %+v", got[0])
+	})
+
+	t.Run("fail on setup error", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.ManualWire()).Add(
+				&tracedComponent{name: "db", setupErr: errors.New("boom")},
+				&tracedComponent{name: "http"},
+			)
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		assert.EqualSlice(t, []anchor.Phase{anchor.PhaseFailed, anchor.PhasePending}, phases(sut.Status()))
+	})
+
+	t.Run("record the start error", func(t *testing.T) {
+		// arrange
+		var (
+			startErr = errors.New("boom")
+			sut      = anchor.New(anchor.ManualWire()).Add(startErrComponent{err: startErr})
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		got := sut.Status()
+		assert.EqualSlice(t, []anchor.Phase{anchor.PhaseFailed}, phases(got))
+		assert.Truef(t, errors.Is(got[0].StartError, startErr), "start error: %v", got[0].StartError)
+	})
+
+	t.Run("record the last probe error", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.New(anchor.ManualWire(),
+				anchor.WithStartTimeout(50*time.Millisecond),
+				anchor.WithFixedReadyCheckBackoff(time.Millisecond),
+			).Add(&tracedComponent{name: "db", probes: 1000})
+		)
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.Internal, code)
+		got := sut.Status()
+		if assert.Equal(t, 1, len(got)) {
+			assert.Error(t, got[0].ProbeError)
+			assert.Truef(t, got[0].Ready.IsZero(), "never ready: %+v", got[0])
+		}
+	})
+
+	t.Run("safe to call while running", func(t *testing.T) {
+		// arrange
+		var (
+			wire = anchor.ManualWire()
+			done = make(chan struct{})
+			sut  = anchor.New(wire).Add(&tracedComponent{name: "db"}, &tracedComponent{name: "http"})
+		)
+
+		go func() {
+			defer close(done)
+			for {
+				select {
+				case <-wire.Done():
+					return
+				default:
+					_ = sut.Status()
+				}
+			}
+		}()
+		go func() {
+			<-sut.Ready()
+			wire.Stop(nil)
+		}()
+
+		// act
+		code := sut.Run()
+
+		// assert
+		<-done
+		assert.Equal(t, anchor.OK, code)
+	})
+
+	t.Run("encode as json", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.ComponentStatus{
+				ComponentTimeline: anchor.ComponentTimeline{Name: "db"},
+				Phase:             anchor.PhaseStarting,
+				ProbeError:        errors.New("not ready"),
+			}
+		)
+
+		// act
+		got, err := json.Marshal(sut)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"db","phase":"starting","probe_error":"not ready"}`, string(got))
+		assert.Falsef(t, strings.Contains(string(got), "start_error"), "json: %s", got)
+	})
+}
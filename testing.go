@@ -1,5 +1,50 @@
 package anchor
 
+import "context"
+
+// TestingM is the part of *testing.M used by the Anchor.
 type TestingM interface {
 	Run() int
 }
+
+// RunTests is a helper for TestMain, that runs the tests in m while the Components are running.
+//
+// The tests are run when all Components are ready, and the Anchor is shut down when they are done.
+// The returned code is the one from the tests if they failed, otherwise the one from the Anchor.
+// If the Anchor fails before it is ready, the tests are not run.
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(anchor.RunTests(m, []anchor.Option{anchor.WithDefaultSlog()}, components...))
+//	}
+func RunTests(m TestingM, opts []Option, components ...Component) int {
+	var (
+		a        = New(WireFunc(context.WithCancel), opts...).Add(components...)
+		done     = make(chan struct{})
+		testCode = make(chan int, 1)
+	)
+
+	go func() {
+		select {
+		case <-a.Ready():
+			testCode <- m.Run()
+			a.Shutdown()
+		case <-done:
+			close(testCode)
+		}
+	}()
+
+	code := a.Run()
+	close(done)
+
+	tc, started := <-testCode
+	if !started && code == OK && len(components) == 0 {
+		// an Anchor without Components is never ready
+		tc, started = m.Run(), true
+	}
+
+	if started && tc != OK {
+		return tc
+	}
+
+	return code
+}
@@ -0,0 +1,113 @@
+package anchor_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+func TestRunTests(t *testing.T) {
+	var (
+		newComponent = func() *fullComponentMock {
+			return &fullComponentMock{
+				CloseFunc: func(ctx context.Context) error { return nil },
+				NameFunc:  func() string { return "component" },
+				SetupFunc: func(ctx context.Context) error { return nil },
+				StartFunc: func(ctx context.Context) error {
+					<-ctx.Done()
+					return nil
+				},
+				ProbeFunc: func(ctx context.Context) error { return nil },
+			}
+		}
+	)
+
+	t.Run("run tests when ready and close after", func(t *testing.T) {
+		// arrange
+		var (
+			component = newComponent()
+			m         = &TestingMMock{}
+		)
+
+		m.RunFunc = func() int {
+			assert.Equal(t, 1, len(component.StartCalls()))
+			assert.Equal(t, 0, len(component.CloseCalls()))
+			return 0
+		}
+
+		// act
+		code := anchor.RunTests(m, nil, component)
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		assert.Equal(t, 1, len(m.RunCalls()))
+		assert.Equal(t, 1, len(component.CloseCalls()))
+	})
+
+	t.Run("return test failure", func(t *testing.T) {
+		// arrange
+		var (
+			m = &TestingMMock{RunFunc: func() int { return 1 }}
+		)
+
+		// act
+		code := anchor.RunTests(m, nil, newComponent())
+
+		// assert
+		assert.Equal(t, 1, code)
+	})
+
+	t.Run("return anchor failure", func(t *testing.T) {
+		// arrange
+		var (
+			component = newComponent()
+			m         = &TestingMMock{RunFunc: func() int { return 0 }}
+		)
+
+		// act
+		code := anchor.RunTests(m, []anchor.Option{
+			anchor.WithReadyCallback(func(ctx context.Context) error {
+				return errors.New("FAIL")
+			}),
+		}, component)
+
+		// assert
+		assert.Equal(t, anchor.Internal, code)
+		assert.Equal(t, 0, len(m.RunCalls()))
+	})
+
+	t.Run("skip tests on setup failure", func(t *testing.T) {
+		// arrange
+		var (
+			component = newComponent()
+			m         = &TestingMMock{RunFunc: func() int { return 0 }}
+		)
+
+		component.SetupFunc = func(ctx context.Context) error {
+			return errors.New("FAIL")
+		}
+
+		// act
+		code := anchor.RunTests(m, nil, component)
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+		assert.Equal(t, 0, len(m.RunCalls()))
+	})
+
+	t.Run("run tests without components", func(t *testing.T) {
+		// arrange
+		var (
+			m = &TestingMMock{RunFunc: func() int { return 2 }}
+		)
+
+		// act
+		code := anchor.RunTests(m, nil)
+
+		// assert
+		assert.Equal(t, 2, code)
+	})
+}
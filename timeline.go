@@ -0,0 +1,147 @@
+package anchor
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Timeline of the startup and shutdown of an Anchor.
+//
+// Times that have not happened yet are zero and left out of the JSON encoding.
+type Timeline struct {
+	Run        time.Time           `json:"run,omitzero"`
+	Ready      time.Time           `json:"ready,omitzero"`
+	Shutdown   time.Time           `json:"shutdown,omitzero"`
+	Done       time.Time           `json:"done,omitzero"`
+	Components []ComponentTimeline `json:"components"`
+}
+
+// ComponentTimeline has the times a Component moved through its lifecycle.
+type ComponentTimeline struct {
+	Name       string    `json:"name"`
+	SetupBegin time.Time `json:"setup_begin,omitzero"`
+	SetupEnd   time.Time `json:"setup_end,omitzero"`
+	StartBegin time.Time `json:"start_begin,omitzero"`
+	Ready      time.Time `json:"ready,omitzero"`
+	CloseBegin time.Time `json:"close_begin,omitzero"`
+	CloseEnd   time.Time `json:"close_end,omitzero"`
+}
+
+// Timeline returns the timings of the Anchor so far.
+func (a *Anchor) Timeline() Timeline {
+	return a.tracker.timeline()
+}
+
+// String renders the Timeline as a table with the duration of Setup, when Start began,
+// the time from Start until the Component was ready and the duration of Close.
+func (t Timeline) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "COMPONENT\tSETUP\tSTART AT\tREADY AFTER\tCLOSE")
+	for _, c := range t.Components {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			c.Name,
+			between(c.SetupBegin, c.SetupEnd),
+			between(t.Run, c.StartBegin),
+			between(c.StartBegin, c.Ready),
+			between(c.CloseBegin, c.CloseEnd),
+		)
+	}
+	_, _ = fmt.Fprintf(w, "total\t\t\t%s\t%s\n", between(t.Run, t.Ready), between(t.Shutdown, t.Done))
+	_ = w.Flush()
+
+	return b.String()
+}
+
+// Gantt renders the Timeline as an ASCII chart, where each Component is a row that is
+// width characters wide.
+//
+//	s Setup
+//	. Started, but not ready
+//	= Ready
+//	c Close
+func (t Timeline) Gantt(width int) string {
+	var (
+		end   = t.end()
+		total = end.Sub(t.Run)
+		name  = 0
+	)
+	for _, c := range t.Components {
+		name = max(name, len(c.Name))
+	}
+
+	column := func(at time.Time) int {
+		if total <= 0 {
+			return 0
+		}
+		return min(width, int(int64(width)*int64(at.Sub(t.Run))/int64(total)))
+	}
+
+	var b strings.Builder
+	for _, c := range t.Components {
+		row := []byte(strings.Repeat(" ", width))
+		fill := func(from, to time.Time, mark byte) {
+			if from.IsZero() {
+				return
+			}
+			if to.IsZero() {
+				to = end
+			}
+			first, last := column(from), column(to)
+			if last == first && first < width {
+				last = first + 1
+			}
+			for i := first; i < last; i++ {
+				row[i] = mark
+			}
+		}
+
+		fill(c.SetupBegin, c.SetupEnd, 's')
+		fill(c.StartBegin, firstOf(c.Ready, c.CloseBegin), '.')
+		fill(c.Ready, c.CloseBegin, '=')
+		fill(c.CloseBegin, c.CloseEnd, 'c')
+		_, _ = fmt.Fprintf(&b, "%-*s |%s|\n", name, c.Name, row)
+	}
+	_, _ = fmt.Fprintf(&b, "%-*s  0%*s\n", name, "", width, total.Round(time.Millisecond))
+
+	return b.String()
+}
+
+// end is the last time in the Timeline.
+func (t Timeline) end() time.Time {
+	end := t.Run
+	for _, at := range []time.Time{t.Ready, t.Shutdown, t.Done} {
+		if at.After(end) {
+			end = at
+		}
+	}
+	for _, c := range t.Components {
+		for _, at := range []time.Time{c.SetupEnd, c.StartBegin, c.Ready, c.CloseEnd} {
+			if at.After(end) {
+				end = at
+			}
+		}
+	}
+
+	return end
+}
+
+func firstOf(times ...time.Time) time.Time {
+	for _, at := range times {
+		if !at.IsZero() {
+			return at
+		}
+	}
+
+	return time.Time{}
+}
+
+func between(from, to time.Time) string {
+	if from.IsZero() || to.IsZero() {
+		return "-"
+	}
+
+	return to.Sub(from).Round(time.Microsecond).String()
+}
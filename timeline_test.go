@@ -0,0 +1,157 @@
+package anchor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+// syncBuffer is written by Components that log after Run returned.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestTimeline(t *testing.T) {
+	var (
+		at = func(ms int) time.Time {
+			return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(ms) * time.Millisecond)
+		}
+		newTimeline = func() anchor.Timeline {
+			return anchor.Timeline{
+				Run:      at(0),
+				Ready:    at(60),
+				Shutdown: at(80),
+				Done:     at(100),
+				Components: []anchor.ComponentTimeline{
+					{Name: "db", SetupBegin: at(0), SetupEnd: at(20), StartBegin: at(40), Ready: at(50), CloseBegin: at(90), CloseEnd: at(100)},
+					{Name: "http", SetupBegin: at(20), SetupEnd: at(40), StartBegin: at(40), Ready: at(60), CloseBegin: at(80), CloseEnd: at(90)},
+				},
+			}
+		}
+	)
+
+	t.Run("render a table", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newTimeline()
+		)
+
+		// act
+		got := sut.String()
+
+		// assert
+		assert.Equal(t, strings.Join([]string{
+			"COMPONENT  SETUP  START AT  READY AFTER  CLOSE",
+			"db         20ms   40ms      10ms         10ms",
+			"http       20ms   40ms      20ms         10ms",
+			"total                       60ms         20ms",
+			"",
+		}, "\n"), got)
+	})
+
+	t.Run("render a gantt chart", func(t *testing.T) {
+		// arrange
+		var (
+			sut = newTimeline()
+		)
+
+		// act
+		got := sut.Gantt(10)
+
+		// assert
+		assert.Equal(t, strings.Join([]string{
+			"db   |ss  .====c|",
+			"http |  ss..==c |",
+			"      0     100ms",
+			"",
+		}, "\n"), got)
+	})
+
+	t.Run("leave out missing times", func(t *testing.T) {
+		// arrange
+		var (
+			sut = anchor.Timeline{
+				Run:        at(0),
+				Components: []anchor.ComponentTimeline{{Name: "db", SetupBegin: at(0)}},
+			}
+		)
+
+		// act
+		got, err := json.Marshal(sut)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, `{"run":"2024-01-01T00:00:00Z","components":[{"name":"db","setup_begin":"2024-01-01T00:00:00Z"}]}`, string(got))
+		assert.Truef(t, strings.Contains(sut.String(), "db         -      -         -            -"), "table:\n%s", sut)
+	})
+
+	t.Run("record the anchor", func(t *testing.T) {
+		// arrange
+		var (
+			wire = anchor.ManualWire()
+			sut  = anchor.New(wire, anchor.WithReadyCallback(func(ctx context.Context) error {
+				wire.Stop(nil)
+				return nil
+			})).Add(&tracedComponent{name: "db"})
+		)
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		got := sut.Timeline()
+		assert.Truef(t, !got.Run.IsZero() && !got.Ready.IsZero() && !got.Shutdown.IsZero() && !got.Done.IsZero(), "timeline: %+v", got)
+		if assert.Equal(t, 1, len(got.Components)) {
+			c := got.Components[0]
+			assert.Equal(t, "db", c.Name)
+			assert.Truef(t, !c.SetupEnd.Before(c.SetupBegin), "setup: %+v", c)
+			assert.Truef(t, !c.Ready.Before(c.StartBegin), "ready: %+v", c)
+			assert.Truef(t, !c.CloseEnd.Before(c.CloseBegin) && !c.CloseBegin.IsZero(), "close: %+v", c)
+		}
+	})
+
+	t.Run("report at ready and shutdown", func(t *testing.T) {
+		// arrange
+		var (
+			buf  syncBuffer
+			wire = anchor.ManualWire()
+			sut  = anchor.New(wire,
+				anchor.WithSlog(slog.New(slog.NewTextHandler(&buf, nil))),
+				anchor.WithTimelineReport(20),
+				anchor.WithReadyCallback(func(ctx context.Context) error {
+					wire.Stop(nil)
+					return nil
+				}),
+			).Add(&tracedComponent{name: "db"})
+		)
+
+		// act
+		sut.Run()
+
+		// assert
+		assert.Truef(t, strings.Contains(buf.String(), "Timeline at ready"), "log: %s", buf.String())
+		assert.Truef(t, strings.Contains(buf.String(), "Timeline at shutdown"), "log: %s", buf.String())
+	})
+}
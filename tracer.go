@@ -0,0 +1,76 @@
+package anchor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Span names and attribute keys used by the Anchor when tracing.
+const (
+	SpanRun   = "anchor.run"
+	SpanSetup = "anchor.setup"
+	SpanStart = "anchor.start"
+	SpanProbe = "anchor.probe"
+	SpanClose = "anchor.close"
+
+	AttributeComponent = "anchor.component"
+	AttributeAttempt   = "anchor.attempt"
+	AttributeExitCode  = "anchor.exit_code"
+)
+
+// Attribute is a key value pair describing a Span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Tracer starts Spans for the lifecycle of an Anchor.
+//
+// Run is traced by a root Span named SpanRun. Each Setup, Start, Probe attempt and
+// Close of a Component is a child Span of it. The Context given to the
+// Component carries its Span, so the Component can create Spans of its own.
+//
+// The interface is small enough to adapt to e.g. OpenTelemetry outside this module.
+type Tracer interface {
+	// Start a Span as a child of the Span in ctx, if any.
+	// The returned Context carries the new Span.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is a traced operation.
+type Span interface {
+	// End the Span. The err is nil when the operation succeeded.
+	End(err error, attrs ...Attribute)
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(_ error, _ ...Attribute) {}
+
+// traced calls fn for the component phase in a Span, that ends with the error or panic of fn.
+// The observers are notified when the phase begins and ends, with the index of the component.
+func (a *Anchor) traced(ctx context.Context, phase string, index int, component fullComponent, fn func(ctx context.Context) error, attrs ...Attribute) (err error) {
+	attrs = append([]Attribute{{Key: AttributeComponent, Value: component.Name()}}, attrs...)
+	ctx, span := a.cfg.tracer.Start(ctx, "anchor."+strings.ToLower(phase), attrs...)
+	a.observe(func(o observer) { o.begin(index, component.Name(), phase, a.cfg.clock.Now()) })
+	defer func() {
+		if panicErr := recover(); panicErr != nil {
+			err = fmt.Errorf("panic: %v", panicErr)
+			span.End(err)
+			a.observe(func(o observer) { o.end(index, component.Name(), phase, a.cfg.clock.Now(), err) })
+			panic(panicErr)
+		}
+
+		span.End(err)
+		a.observe(func(o observer) { o.end(index, component.Name(), phase, a.cfg.clock.Now(), err) })
+	}()
+
+	return fn(ctx)
+}
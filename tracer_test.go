@@ -0,0 +1,187 @@
+package anchor_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyuff/anchor"
+	"github.com/kyuff/anchor/internal/assert"
+)
+
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]any
+	err    error
+	ended  bool
+}
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (tr *recordingTracer) Start(ctx context.Context, name string, attrs ...anchor.Attribute) (context.Context, anchor.Span) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	span := &recordedSpan{name: name, attrs: map[string]any{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		span.parent = parent.name
+	}
+	for _, attr := range attrs {
+		span.attrs[attr.Key] = attr.Value
+	}
+
+	tr.spans = append(tr.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), &recordingSpan{tr: tr, span: span}
+}
+
+func (tr *recordingTracer) find(name, component string) []recordedSpan {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	var found []recordedSpan
+	for _, span := range tr.spans {
+		if span.name == name && (component == "" || span.attrs[anchor.AttributeComponent] == component) {
+			found = append(found, *span)
+		}
+	}
+
+	return found
+}
+
+type recordingSpan struct {
+	tr   *recordingTracer
+	span *recordedSpan
+}
+
+func (s *recordingSpan) End(err error, attrs ...anchor.Attribute) {
+	s.tr.mu.Lock()
+	defer s.tr.mu.Unlock()
+
+	s.span.err = err
+	s.span.ended = true
+	for _, attr := range attrs {
+		s.span.attrs[attr.Key] = attr.Value
+	}
+}
+
+type tracedComponent struct {
+	name     string
+	probes   int
+	setupErr error
+	inSpan   []string
+}
+
+func (c *tracedComponent) Name() string {
+	return c.name
+}
+
+func (c *tracedComponent) Setup(ctx context.Context) error {
+	c.record(ctx)
+	return c.setupErr
+}
+
+func (c *tracedComponent) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (c *tracedComponent) Probe(ctx context.Context) error {
+	c.probes--
+	if c.probes > 0 {
+		return errors.New("not ready")
+	}
+
+	return nil
+}
+
+func (c *tracedComponent) Close(ctx context.Context) error {
+	c.record(ctx)
+	return nil
+}
+
+func (c *tracedComponent) record(ctx context.Context) {
+	if span, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		c.inSpan = append(c.inSpan, span.name)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	t.Run("trace the lifecycle", func(t *testing.T) {
+		// arrange
+		var (
+			tracer    = &recordingTracer{}
+			wire      = anchor.ManualWire()
+			component = &tracedComponent{name: "db", probes: 2}
+			sut       = anchor.New(wire,
+				anchor.WithTracer(tracer),
+				anchor.WithFixedReadyCheckBackoff(time.Millisecond),
+				anchor.WithReadyCallback(func(ctx context.Context) error {
+					wire.Stop(nil)
+					return nil
+				}),
+			).Add(component)
+		)
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.OK, code)
+		run := tracer.find(anchor.SpanRun, "")
+		if assert.Equal(t, 1, len(run)) {
+			assert.Equal(t, "", run[0].parent)
+			assert.Equal(t, true, run[0].ended)
+			assert.NoError(t, run[0].err)
+			assert.Equal[any](t, anchor.OK, run[0].attrs[anchor.AttributeExitCode])
+		}
+		for _, name := range []string{anchor.SpanSetup, anchor.SpanStart, anchor.SpanClose} {
+			spans := tracer.find(name, "db")
+			if assert.Equalf(t, 1, len(spans), "span %s", name) {
+				assert.Equal(t, anchor.SpanRun, spans[0].parent)
+				assert.Equal(t, true, spans[0].ended)
+			}
+		}
+		probes := tracer.find(anchor.SpanProbe, "db")
+		if assert.Truef(t, len(probes) >= 2, "probe attempts: %d", len(probes)) {
+			last := probes[len(probes)-1]
+			assert.Error(t, probes[0].err)
+			assert.Equal[any](t, 1, probes[0].attrs[anchor.AttributeAttempt])
+			assert.NoError(t, last.err)
+			assert.Equal[any](t, len(probes), last.attrs[anchor.AttributeAttempt])
+		}
+		assert.EqualSlice(t, []string{anchor.SpanSetup, anchor.SpanClose}, component.inSpan)
+	})
+
+	t.Run("end spans with errors", func(t *testing.T) {
+		// arrange
+		var (
+			tracer    = &recordingTracer{}
+			setupErr  = errors.New("setup failed")
+			component = &tracedComponent{name: "db", setupErr: setupErr}
+			sut       = anchor.New(anchor.ManualWire(), anchor.WithTracer(tracer)).Add(component)
+		)
+
+		// act
+		code := sut.Run()
+
+		// assert
+		assert.Equal(t, anchor.SetupFailed, code)
+		setup := tracer.find(anchor.SpanSetup, "db")
+		if assert.Equal(t, 1, len(setup)) {
+			assert.Truef(t, errors.Is(setup[0].err, setupErr), "setup error: %v", setup[0].err)
+		}
+		run := tracer.find(anchor.SpanRun, "")
+		if assert.Equal(t, 1, len(run)) {
+			assert.Truef(t, errors.Is(run[0].err, setupErr), "run error: %v", run[0].err)
+			assert.Equal[any](t, anchor.SetupFailed, run[0].attrs[anchor.AttributeExitCode])
+		}
+	})
+}
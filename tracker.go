@@ -0,0 +1,143 @@
+package anchor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// tracker records the Timeline and Status of an Anchor.
+// The Components are kept by their index in the Anchor, as they can share a name.
+type tracker struct {
+	mu     sync.Mutex
+	t      Timeline
+	status []ComponentStatus
+}
+
+func newTracker() *tracker {
+	return &tracker{}
+}
+
+func (tr *tracker) timeline() Timeline {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	t := tr.t
+	t.Components = append([]ComponentTimeline(nil), tr.t.Components...)
+	return t
+}
+
+func (tr *tracker) snapshot() []ComponentStatus {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	status := append([]ComponentStatus(nil), tr.status...)
+	for i := range status {
+		status[i].ComponentTimeline = tr.t.Components[i]
+	}
+
+	return status
+}
+
+func (tr *tracker) component(index int) (*ComponentTimeline, *ComponentStatus) {
+	return &tr.t.Components[index], &tr.status[index]
+}
+
+func (tr *tracker) add(name string) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.t.Components = append(tr.t.Components, ComponentTimeline{Name: name})
+	tr.status = append(tr.status, ComponentStatus{Phase: PhasePending})
+}
+
+func (tr *tracker) run(_ []string, at time.Time) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.t.Run = at
+	for i := range tr.status {
+		tr.status[i].enter(PhasePending, at)
+	}
+}
+
+func (tr *tracker) begin(index int, _, phase string, at time.Time) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	c, s := tr.component(index)
+	switch phase {
+	case "Setup":
+		c.SetupBegin = at
+		s.enter(PhaseSettingUp, at)
+	case "Start":
+		c.StartBegin = at
+		s.enter(PhaseStarting, at)
+	case "Close":
+		c.CloseBegin = at
+		s.enter(PhaseClosing, at)
+	}
+}
+
+func (tr *tracker) end(index int, _, phase string, at time.Time, err error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	c, s := tr.component(index)
+	switch phase {
+	case "Setup":
+		c.SetupEnd = at
+		s.enter(PhaseSetUp, at)
+	case "Start":
+		s.StartError = err
+		if errors.Is(err, context.Canceled) {
+			// the Component exits as the Anchor shuts down
+			return
+		}
+	case "Probe":
+		s.ProbeError = err
+		if err != nil {
+			return
+		}
+		if c.Ready.IsZero() {
+			c.Ready = at
+		}
+		if s.Phase == PhaseStarting {
+			s.enter(PhaseReady, at)
+		}
+	case "Close":
+		c.CloseEnd = at
+		s.enter(PhaseClosed, at)
+	}
+
+	if err != nil {
+		s.enter(PhaseFailed, at)
+	}
+}
+
+func (tr *tracker) ready(at time.Time) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.t.Ready = at
+	for i := range tr.status {
+		if tr.status[i].Phase == PhaseReady {
+			tr.status[i].enter(PhaseRunning, at)
+		}
+	}
+}
+
+func (tr *tracker) shutdown(_ string, at time.Time) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.t.Shutdown = at
+}
+
+func (tr *tracker) done(_ int, at time.Time) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	tr.t.Done = at
+}
@@ -39,6 +39,9 @@ func DefaultSignalWire() Wire {
 
 // TestingWire returns a Wire for use in testing.
 // It will Run the tests and and then signal the application to shutdown.
+//
+// The result of the tests is discarded. Use [RunTests] to have it returned,
+// or [ManualWire] to control the shutdown from within a test.
 func TestingWire(m TestingM) Wire {
 	return WireFunc(func(ctx context.Context) (context.Context, context.CancelFunc) {
 		wireCtx, cancel := context.WithCancel(ctx)